@@ -0,0 +1,51 @@
+//go:build debug
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/api"
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/spf13/pflag"
+)
+
+var debugStart = pflag.String("debug-start", "",
+	"Start a fake clock at the given RFC 3339 time")
+
+// newClock returns a fake clock when the server is started with
+// --debug-start. The clock only moves on POST /debug/clock, which
+// lets integration tests simulate weeks of reviews in seconds.
+func newClock() (clock.Clock, error) {
+	if *debugStart == "" {
+		return clock.Real{}, nil
+	}
+	start, err := time.Parse(time.RFC3339, *debugStart)
+	if err != nil {
+		return nil, err
+	}
+	return clock.NewFake(start), nil
+}
+
+func registerDebugHandlers(mux *http.ServeMux, clk clock.Clock) {
+	fake, ok := clk.(*clock.Fake)
+	if !ok {
+		return
+	}
+	// POST /debug/clock?advance=36h moves the clock forward.
+	mux.HandleFunc("POST /debug/clock", func(w http.ResponseWriter, r *http.Request) {
+		if raw := r.URL.Query().Get("advance"); raw != "" {
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				fmt.Println(err)
+				writeError(w, http.StatusBadRequest, api.ErrorBadRequest, err.Error())
+				return
+			}
+			fake.Advance(d)
+		}
+		io.WriteString(w, fake.Now().Format(time.RFC3339))
+	})
+}
@@ -0,0 +1,45 @@
+package main
+
+import "sync"
+
+// keyedMutex is a set of mutexes, one for every key. A mutex
+// is removed when nobody holds or waits for it, so keys may
+// be as many as topics of all users.
+type keyedMutex[K comparable] struct {
+	m     sync.Mutex
+	locks map[K]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	// refs is the number of goroutines holding
+	// or waiting for the mutex.
+	refs int
+}
+
+// Lock locks the mutex of the key and returns
+// the function which unlocks it.
+func (km *keyedMutex[K]) Lock(key K) (unlock func()) {
+	km.m.Lock()
+	if km.locks == nil {
+		km.locks = make(map[K]*keyedLock)
+	}
+	l, ok := km.locks[key]
+	if !ok {
+		l = new(keyedLock)
+		km.locks[key] = l
+	}
+	l.refs++
+	km.m.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		km.m.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(km.locks, key)
+		}
+		km.m.Unlock()
+	}
+}
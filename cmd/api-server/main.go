@@ -7,44 +7,102 @@ import (
 	"os"
 
 	"github.com/Ayaya-zx/mem-flow/internal/auth"
-	"github.com/Ayaya-zx/mem-flow/internal/repository/inmem"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	"github.com/Ayaya-zx/mem-flow/internal/scheduler"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 func main() {
 	var port int
+	var schedulerName string
 
 	v := viper.New()
 	v.SetDefault("Port", 8765)
+	v.SetDefault("Scheduler", scheduler.DefaultName)
+	v.SetDefault("Storage", storageMemory)
+	v.SetDefault("Data", "")
 
 	v.AutomaticEnv()
 	v.SetEnvPrefix("MEMFLOW")
 	v.BindEnv("Port", "port")
+	v.BindEnv("Scheduler", "scheduler")
+	v.BindEnv("Storage", "storage")
+	v.BindEnv("Data", "data")
 
 	pflag.IntVarP(&port, "port", "p", 8765, "Port")
+	pflag.StringVarP(&schedulerName, "scheduler", "s", scheduler.DefaultName, "Default scheduler")
+	pflag.String("storage", storageMemory, "Storage backend: memory, sqlite, journal, bolt or markdown")
+	pflag.String("data", "", "Path to the data of the storage (default memflow.db for sqlite, memflow-data for journal, memflow.bolt for bolt, memflow-notes for markdown)")
 	pflag.Parse()
 	if err := v.BindPFlag("Port", pflag.Lookup("port")); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	if err := v.BindPFlag("Scheduler", pflag.Lookup("scheduler")); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := v.BindPFlag("Storage", pflag.Lookup("storage")); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := v.BindPFlag("Data", pflag.Lookup("data")); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if _, err := scheduler.New(v.GetString("Scheduler"), &entity.Settings{}); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	clk, err := newClock()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	store, err := openStorage(v.GetString("Storage"), v.GetString("Data"), clk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer store.close()
 
 	server := newTopicServer(
-		auth.NewAuthService(inmem.NewInmemUserRepository()),
-		inmem.NewInmemUserTopicRepository(inmem.NewInmemTopicRepositoryFactory()),
+		auth.NewAuthService(store.users),
+		store.userTopics,
+		store.settings,
+		store.reviews,
+		v.GetString("Scheduler"),
+		clk,
 	)
 
 	mux := http.NewServeMux()
 	mux.Handle("GET /topics", server.authMiddleware(http.HandlerFunc(server.getAllTopicsHandler)))
 	mux.Handle("POST /topics", server.authMiddleware(http.HandlerFunc(server.createTopicHandler)))
+	mux.Handle("GET /topics/due", server.authMiddleware(http.HandlerFunc(server.getDueTopicsHandler)))
 	mux.Handle("GET /topics/{id}", server.authMiddleware(http.HandlerFunc(server.getTopicHandler)))
 	mux.Handle("PATCH /topics/{id}", server.authMiddleware(http.HandlerFunc(server.repeateTopicHandler)))
 	mux.Handle("DELETE /topics/{id}", server.authMiddleware(http.HandlerFunc(server.deleteTopicHandler)))
+	mux.Handle("POST /topics/{id}/suspend", server.authMiddleware(server.suspendTopicHandler()))
+	mux.Handle("POST /topics/{id}/bury", server.authMiddleware(server.buryTopicHandler()))
+	mux.Handle("POST /topics/{id}/unsuspend", server.authMiddleware(server.unsuspendTopicHandler()))
+	mux.Handle("GET /topics/{id}/reviews", server.authMiddleware(http.HandlerFunc(server.getTopicReviewsHandler)))
+	mux.Handle("GET /reviews", server.authMiddleware(http.HandlerFunc(server.getReviewsHandler)))
+	mux.Handle("GET /settings", server.authMiddleware(http.HandlerFunc(server.getSettingsHandler)))
+	mux.Handle("PUT /settings", server.authMiddleware(http.HandlerFunc(server.updateSettingsHandler)))
+	mux.Handle("GET /vacation", server.authMiddleware(http.HandlerFunc(server.getVacationHandler)))
+	mux.Handle("PUT /vacation", server.authMiddleware(http.HandlerFunc(server.updateVacationHandler)))
+	mux.Handle("DELETE /vacation", server.authMiddleware(http.HandlerFunc(server.deleteVacationHandler)))
+	mux.Handle("GET /stats/forecast", server.authMiddleware(http.HandlerFunc(server.forecastHandler)))
 	mux.HandleFunc("GET /example", http.HandlerFunc(server.exampleHandler))
 	mux.HandleFunc("POST /registration", server.registrationHandler)
 	mux.HandleFunc("POST /auth", server.authenticationHandler)
 
-	err := http.ListenAndServe(fmt.Sprintf(":%d", v.GetInt("Port")), mux)
+	registerDebugHandlers(mux, clk)
+
+	err = http.ListenAndServe(fmt.Sprintf(":%d", v.GetInt("Port")), mux)
 	if err != nil {
 		log.Fatal(err)
 	}
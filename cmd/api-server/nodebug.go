@@ -0,0 +1,15 @@
+//go:build !debug
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+)
+
+func newClock() (clock.Clock, error) {
+	return clock.Real{}, nil
+}
+
+func registerDebugHandlers(*http.ServeMux, clock.Clock) {}
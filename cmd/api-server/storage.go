@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/boltdb"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/inmem"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/journal"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/markdown"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/sqlite"
+)
+
+// Names of the storage backends accepted by --storage.
+const (
+	storageMemory   = "memory"
+	storageSqlite   = "sqlite"
+	storageJournal  = "journal"
+	storageBolt     = "bolt"
+	storageMarkdown = "markdown"
+)
+
+// storage holds the repositories of a backend.
+type storage struct {
+	users      repo.UserRepository
+	userTopics repo.UserTopicRepository
+	settings   repo.SettingsRepository
+	reviews    repo.ReviewRepository
+	// close releases the backend.
+	close func() error
+}
+
+// openStorage opens the backend with the given name which keeps
+// its data at path. The memory backend ignores the path.
+func openStorage(name, path string, clk clock.Clock) (*storage, error) {
+	switch name {
+	case storageMemory:
+		return &storage{
+			users:      inmem.NewInmemUserRepository(),
+			userTopics: inmem.NewInmemUserTopicRepository(inmem.NewInmemTopicRepositoryFactory(clk)),
+			settings:   inmem.NewInmemSettingsRepository(),
+			reviews:    inmem.NewInmemReviewRepository(),
+			close:      func() error { return nil },
+		}, nil
+	case storageSqlite:
+		if path == "" {
+			path = "memflow.db"
+		}
+		db, err := sqlite.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			users: sqlite.NewSqliteUserRepository(db),
+			userTopics: sqlite.NewSqliteUserTopicRepository(
+				db, sqlite.NewSqliteTopicRepositoryFactory(db, clk)),
+			settings: sqlite.NewSqliteSettingsRepository(db),
+			reviews:  sqlite.NewSqliteReviewRepository(db),
+			close:    db.Close,
+		}, nil
+	case storageJournal:
+		if path == "" {
+			path = "memflow-data"
+		}
+		j, err := journal.Open(path, journal.DefaultSnapshotEvery, clk)
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			users:      j.UserRepository(),
+			userTopics: j.UserTopicRepository(),
+			settings:   j.SettingsRepository(),
+			reviews:    j.ReviewRepository(),
+			close:      j.Close,
+		}, nil
+	case storageBolt:
+		if path == "" {
+			path = "memflow.bolt"
+		}
+		db, err := boltdb.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			users:      boltdb.NewBoltUserRepository(db),
+			userTopics: boltdb.NewBoltUserTopicRepository(db, clk),
+			settings:   boltdb.NewBoltSettingsRepository(db),
+			reviews:    boltdb.NewBoltReviewRepository(db),
+			close:      db.Close,
+		}, nil
+	case storageMarkdown:
+		if path == "" {
+			path = "memflow-notes"
+		}
+		// Only topics are kept as Markdown files, the rest is
+		// journaled next to the directories of the users.
+		j, err := journal.Open(filepath.Join(path, ".journal"), journal.DefaultSnapshotEvery, clk)
+		if err != nil {
+			return nil, err
+		}
+		userTopics := markdown.NewMarkdownUserTopicRepository(path, clk)
+		return &storage{
+			users:      markdown.WithUserNameCheck(j.UserRepository()),
+			userTopics: userTopics,
+			settings:   j.SettingsRepository(),
+			reviews:    j.ReviewRepository(),
+			close: func() error {
+				return errors.Join(userTopics.Close(), j.Close())
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown storage %q", name)
+}
@@ -6,14 +6,24 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Ayaya-zx/mem-flow/internal/api"
 	"github.com/Ayaya-zx/mem-flow/internal/auth"
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
 	"github.com/Ayaya-zx/mem-flow/internal/common"
 	"github.com/Ayaya-zx/mem-flow/internal/entity"
 	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/scheduler"
+	"github.com/Ayaya-zx/mem-flow/internal/stats"
+)
+
+const (
+	defaultForecastDays = 30
+	maxForecastDays     = 365
 )
 
 type clientError string
@@ -23,14 +33,41 @@ func (e clientError) Error() string {
 }
 
 type topicServer struct {
-	authService   *auth.AuthService
-	userTopicRepo repo.UserTopicRepository
+	// vacationMu serializes vacation changes of the same
+	// user, so a vacation is ended only once.
+	vacationMu keyedMutex[string]
+	// topicMu serializes repetitions of the same topic,
+	// so none of them is lost.
+	topicMu          keyedMutex[topicKey]
+	authService      *auth.AuthService
+	userTopicRepo    repo.UserTopicRepository
+	settingsRepo     repo.SettingsRepository
+	reviewRepo       repo.ReviewRepository
+	defaultScheduler string
+	clock            clock.Clock
+}
+
+// topicKey identifies a topic among the topics of all users.
+type topicKey struct {
+	name string
+	id   int
 }
 
-func newTopicServer(authService *auth.AuthService, userTopicRepo repo.UserTopicRepository) *topicServer {
+func newTopicServer(
+	authService *auth.AuthService,
+	userTopicRepo repo.UserTopicRepository,
+	settingsRepo repo.SettingsRepository,
+	reviewRepo repo.ReviewRepository,
+	defaultScheduler string,
+	clk clock.Clock,
+) *topicServer {
 	return &topicServer{
-		authService:   authService,
-		userTopicRepo: userTopicRepo,
+		authService:      authService,
+		userTopicRepo:    userTopicRepo,
+		settingsRepo:     settingsRepo,
+		reviewRepo:       reviewRepo,
+		defaultScheduler: defaultScheduler,
+		clock:            clk,
 	}
 }
 
@@ -47,20 +84,54 @@ func getToken(bearer string) (string, error) {
 
 func (s *topicServer) handleError(w http.ResponseWriter, _ *http.Request, err error) {
 	fmt.Println(err)
-	if _, notExist := err.(common.TopicNotExistsError); notExist {
-		w.WriteHeader(404)
-		return
+	status, code := errorStatus(err)
+	message := err.Error()
+	if _, ok := err.(common.UserNotExistError); ok {
+		// The message must not tell which user names exist.
+		message = "incorrect name or password"
+	}
+	if status == http.StatusInternalServerError {
+		// Internal errors may reveal details of the server.
+		message = http.StatusText(status)
 	}
+	writeError(w, status, code, message)
+}
 
-	_, badTitle := err.(common.TopicTitleError)
-	_, clientErr := err.(clientError)
-	_, invalidAuth := err.(common.InvalidAuthData)
-	if badTitle || clientErr || invalidAuth {
-		w.WriteHeader(400)
-		return
+// errorStatus returns the status code of the response
+// to the error and the code written to its body.
+func errorStatus(err error) (int, string) {
+	switch err.(type) {
+	case common.TopicNotExistsError:
+		return http.StatusNotFound, api.ErrorTopicNotFound
+	case common.TopicSuspendedError:
+		return http.StatusConflict, api.ErrorTopicSuspended
+	case common.TopicTitleConflictError:
+		return http.StatusConflict, api.ErrorTitleConflict
+	case common.UserAlreadyExistsError:
+		return http.StatusConflict, api.ErrorUserExists
+	case common.UserNotExistError:
+		return http.StatusUnauthorized, api.ErrorUnauthorized
+	case common.TopicTitleError,
+		common.EmptyUserName,
+		clientError,
+		common.InvalidAuthData,
+		common.UnknownSchedulerError,
+		common.InvalidSettingsError:
+		return http.StatusBadRequest, api.ErrorBadRequest
 	}
+	return http.StatusInternalServerError, api.ErrorInternal
+}
 
-	w.WriteHeader(500)
+// writeError writes an error response with api.ErrorResponse body.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	data, err := json.Marshal(api.ErrorResponse{Code: code, Message: message})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
 }
 
 func (s *topicServer) authMiddleware(next http.Handler) http.Handler {
@@ -68,13 +139,13 @@ func (s *topicServer) authMiddleware(next http.Handler) http.Handler {
 		token, err := getToken(r.Header.Get("Authorization"))
 		if err != nil {
 			fmt.Println(err)
-			w.WriteHeader(401)
+			writeError(w, http.StatusUnauthorized, api.ErrorUnauthorized, err.Error())
 			return
 		}
 		name, err := s.authService.Validate(token)
 		if err != nil {
 			fmt.Println(err)
-			w.WriteHeader(401)
+			writeError(w, http.StatusUnauthorized, api.ErrorUnauthorized, err.Error())
 			return
 		}
 		ctx := context.WithValue(r.Context(), "username", name)
@@ -83,6 +154,114 @@ func (s *topicServer) authMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// topicRepository returns the topic repository of the user.
+// If the user has returned from a vacation, the topics affected
+// by it are rescheduled first.
+func (s *topicServer) topicRepository(name string) (repo.TopicRepository, error) {
+	topicRepo, err := s.userTopicRepo.GetUserTopicRepository(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Most requests find no ended vacation and take no lock.
+	settings, ended, err := s.vacationEnded(name)
+	if err != nil {
+		return nil, err
+	}
+	if !ended {
+		return topicRepo, nil
+	}
+
+	unlock := s.vacationMu.Lock(name)
+	defer unlock()
+	// Another request may have ended the vacation meanwhile.
+	settings, ended, err = s.vacationEnded(name)
+	if err != nil {
+		return nil, err
+	}
+	if !ended {
+		return topicRepo, nil
+	}
+	err = s.endVacation(name, topicRepo, settings, settings.Vacation.End)
+	if err != nil {
+		return nil, err
+	}
+	return topicRepo, nil
+}
+
+// vacationEnded returns the settings of the user and tells
+// whether they have a vacation which is over but not ended yet.
+func (s *topicServer) vacationEnded(name string) (*entity.Settings, bool, error) {
+	settings, err := s.settingsRepo.GetSettings(name)
+	if err != nil {
+		return nil, false, err
+	}
+	ended := settings.Vacation != nil && !s.clock.Now().Before(settings.Vacation.End)
+	return settings, ended, nil
+}
+
+// endVacation reschedules the topics affected by the vacation
+// which ended at end and removes the vacation from the settings.
+// It must be called with vacationMu of the user held.
+func (s *topicServer) endVacation(
+	name string,
+	topicRepo repo.TopicRepository,
+	settings *entity.Settings,
+	end time.Time,
+) error {
+	topics, err := topicRepo.GetAllTopics()
+	if err != nil {
+		return err
+	}
+	for _, t := range scheduler.ApplyVacation(topics, settings.Vacation, end) {
+		err = topicRepo.UpdateTopic(t)
+		if err != nil {
+			return err
+		}
+	}
+	settings.Vacation = nil
+	return s.settingsRepo.SetSettings(name, settings)
+}
+
+// userScheduler returns the scheduler chosen by the user
+// or the server default one. Unless the user disabled it,
+// the intervals are fuzzed and, if the user asked for it,
+// balanced across the days using the topics of topicRepo.
+func (s *topicServer) userScheduler(name string, topicRepo repo.TopicRepository) (entity.Scheduler, error) {
+	settings, err := s.settingsRepo.GetSettings(name)
+	if err != nil {
+		return nil, err
+	}
+
+	sched, err := s.settingsScheduler(settings)
+	if err != nil {
+		return nil, err
+	}
+	if settings.DisableFuzz {
+		return sched, nil
+	}
+
+	fuzz := &scheduler.Fuzz{Scheduler: sched, Max: time.Duration(settings.MaxInterval)}
+	if settings.LoadBalance {
+		topics, err := topicRepo.GetAllTopics()
+		if err != nil {
+			return nil, err
+		}
+		fuzz.Load = scheduler.DueLoad(topics)
+	}
+	return fuzz, nil
+}
+
+// settingsScheduler returns the scheduler configured
+// by the settings without any randomness.
+func (s *topicServer) settingsScheduler(settings *entity.Settings) (entity.Scheduler, error) {
+	schedName := settings.Scheduler
+	if schedName == "" {
+		schedName = s.defaultScheduler
+	}
+	return scheduler.New(schedName, settings)
+}
+
 func (s *topicServer) registrationHandler(w http.ResponseWriter, r *http.Request) {
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -148,7 +327,7 @@ func (s *topicServer) exampleHandler(w http.ResponseWriter, r *http.Request) {
 
 func (s *topicServer) getAllTopicsHandler(w http.ResponseWriter, r *http.Request) {
 	name := r.Context().Value("username").(string)
-	topicRepo, err := s.userTopicRepo.GetUserTopicRepository(name)
+	topicRepo, err := s.topicRepository(name)
 	if err != nil {
 		s.handleError(w, r, err)
 		return
@@ -159,6 +338,54 @@ func (s *topicServer) getAllTopicsHandler(w http.ResponseWriter, r *http.Request
 		s.handleError(w, r, err)
 		return
 	}
+
+	var result any = topics
+	switch group := r.URL.Query().Get("group"); group {
+	case "":
+	case "box":
+		result = groupByBox(topics)
+	default:
+		s.handleError(w, r, clientError(fmt.Sprintf("unknown group %q", group)))
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	w.Write(data)
+}
+
+// getDueTopicsHandler returns topics due now, the most overdue first.
+// The number of topics is limited by the limit query parameter.
+func (s *topicServer) getDueTopicsHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+	topicRepo, err := s.topicRepository(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil {
+			s.handleError(w, r, clientError(err.Error()))
+			return
+		}
+		if limit < 0 {
+			s.handleError(w, r, clientError("limit is negative"))
+			return
+		}
+	}
+
+	topics, err := topicRepo.GetDueTopics(s.clock.Now(), limit)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
 	data, err := json.Marshal(topics)
 	if err != nil {
 		s.handleError(w, r, err)
@@ -167,9 +394,31 @@ func (s *topicServer) getAllTopicsHandler(w http.ResponseWriter, r *http.Request
 	w.Write(data)
 }
 
+// groupByBox splits topics by their Leitner boxes. Topics which
+// have not been put into a box yet belong to the first one.
+func groupByBox(topics []*entity.Topic) []api.TopicBoxGroup {
+	byBox := make(map[int][]*entity.Topic)
+	for _, t := range topics {
+		box := max(t.State.Box, 1)
+		byBox[box] = append(byBox[box], t)
+	}
+
+	groups := make([]api.TopicBoxGroup, 0, len(byBox))
+	for box, topics := range byBox {
+		slices.SortFunc(topics, func(a, b *entity.Topic) int {
+			return a.Id - b.Id
+		})
+		groups = append(groups, api.TopicBoxGroup{Box: box, Topics: topics})
+	}
+	slices.SortFunc(groups, func(a, b api.TopicBoxGroup) int {
+		return a.Box - b.Box
+	})
+	return groups
+}
+
 func (s *topicServer) createTopicHandler(w http.ResponseWriter, r *http.Request) {
 	name := r.Context().Value("username").(string)
-	topicRepo, err := s.userTopicRepo.GetUserTopicRepository(name)
+	topicRepo, err := s.topicRepository(name)
 	if err != nil {
 		s.handleError(w, r, err)
 		return
@@ -185,9 +434,17 @@ func (s *topicServer) createTopicHandler(w http.ResponseWriter, r *http.Request)
 	err = json.Unmarshal(data, &req)
 	if err != nil {
 		s.handleError(w, r, clientError(err.Error()))
+		return
 	}
 
-	id, err := topicRepo.AddTopic(req.Title)
+	sched, err := s.userScheduler(name, topicRepo)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	// The user's scheduler chooses the first repetition.
+	id, err := topicRepo.AddTopic(req.Title, sched)
 	if err != nil {
 		s.handleError(w, r, err)
 		return
@@ -200,7 +457,7 @@ func (s *topicServer) createTopicHandler(w http.ResponseWriter, r *http.Request)
 
 func (s *topicServer) getTopicHandler(w http.ResponseWriter, r *http.Request) {
 	name := r.Context().Value("username").(string)
-	topicRepo, err := s.userTopicRepo.GetUserTopicRepository(name)
+	topicRepo, err := s.topicRepository(name)
 	if err != nil {
 		s.handleError(w, r, err)
 		return
@@ -230,7 +487,7 @@ func (s *topicServer) getTopicHandler(w http.ResponseWriter, r *http.Request) {
 
 func (s *topicServer) repeateTopicHandler(w http.ResponseWriter, r *http.Request) {
 	name := r.Context().Value("username").(string)
-	topicRepo, err := s.userTopicRepo.GetUserTopicRepository(name)
+	topicRepo, err := s.topicRepository(name)
 	if err != nil {
 		s.handleError(w, r, err)
 		return
@@ -243,18 +500,71 @@ func (s *topicServer) repeateTopicHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.handleError(w, r, clientError(err.Error()))
+		return
+	}
+
+	// A repetition without a grade counts as a good one.
+	grade := entity.GradeGood
+	if len(data) > 0 {
+		var req api.RepeatTopicRequest
+		err = json.Unmarshal(data, &req)
+		if err != nil {
+			s.handleError(w, r, clientError(err.Error()))
+			return
+		}
+		if req.Grade != nil {
+			grade = *req.Grade
+		}
+	}
+	if !grade.Valid() {
+		s.handleError(w, r, clientError(fmt.Sprintf("invalid grade %d", grade)))
+		return
+	}
+
+	// The topic must not change between reading it and
+	// storing the result of the repetition.
+	unlock := s.topicMu.Lock(topicKey{name, id})
+	defer unlock()
+
 	topic, err := topicRepo.GetTopicById(id)
 	if err != nil {
 		s.handleError(w, r, err)
 		return
 	}
 
-	topic.Repeat()
+	now := s.clock.Now()
+	if !topic.Active(now) {
+		s.handleError(w, r, common.TopicSuspendedError(
+			fmt.Sprintf("topic with id %d is suspended or buried", id)))
+		return
+	}
+
+	sched, err := s.userScheduler(name, topicRepo)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	event := topic.Repeat(sched, grade, now)
+	err = topicRepo.UpdateTopic(topic)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	err = s.reviewRepo.AddReview(name, event)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
 }
 
 func (s *topicServer) deleteTopicHandler(w http.ResponseWriter, r *http.Request) {
 	name := r.Context().Value("username").(string)
-	topicRepo, err := s.userTopicRepo.GetUserTopicRepository(name)
+	topicRepo, err := s.topicRepository(name)
 	if err != nil {
 		s.handleError(w, r, err)
 		return
@@ -272,4 +582,315 @@ func (s *topicServer) deleteTopicHandler(w http.ResponseWriter, r *http.Request)
 		s.handleError(w, r, err)
 		return
 	}
+
+	err = s.reviewRepo.RemoveTopicReviews(name, id)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+}
+
+// modifyTopicHandler returns a handler which applies modify
+// to the topic with the id from the path.
+func (s *topicServer) modifyTopicHandler(modify func(topicRepo repo.TopicRepository, id int) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.Context().Value("username").(string)
+		topicRepo, err := s.topicRepository(name)
+		if err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+
+		raw := r.PathValue("id")
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			s.handleError(w, r, clientError(err.Error()))
+			return
+		}
+
+		err = modify(topicRepo, id)
+		if err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+	}
+}
+
+func (s *topicServer) suspendTopicHandler() http.HandlerFunc {
+	return s.modifyTopicHandler(func(topicRepo repo.TopicRepository, id int) error {
+		return topicRepo.SuspendTopic(id)
+	})
+}
+
+// buryTopicHandler buries the topic until the next day.
+func (s *topicServer) buryTopicHandler() http.HandlerFunc {
+	return s.modifyTopicHandler(func(topicRepo repo.TopicRepository, id int) error {
+		y, m, d := s.clock.Now().Local().Date()
+		tomorrow := time.Date(y, m, d+1, 0, 0, 0, 0, time.Local)
+		return topicRepo.BuryTopic(id, tomorrow)
+	})
+}
+
+func (s *topicServer) unsuspendTopicHandler() http.HandlerFunc {
+	return s.modifyTopicHandler(func(topicRepo repo.TopicRepository, id int) error {
+		return topicRepo.UnsuspendTopic(id)
+	})
+}
+
+func (s *topicServer) getTopicReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+	topicRepo, err := s.topicRepository(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	raw := r.PathValue("id")
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		s.handleError(w, r, clientError(err.Error()))
+		return
+	}
+
+	// Make sure the topic exists to answer with 404 otherwise.
+	_, err = topicRepo.GetTopicById(id)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	reviews, err := s.reviewRepo.GetTopicReviews(name, id)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(reviews)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (s *topicServer) getReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+	reviews, err := s.reviewRepo.GetReviews(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(reviews)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (s *topicServer) getSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+	settings, err := s.settingsRepo.GetSettings(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (s *topicServer) updateSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.handleError(w, r, clientError(err.Error()))
+		return
+	}
+
+	settings := new(entity.Settings)
+	err = json.Unmarshal(data, settings)
+	if err != nil {
+		s.handleError(w, r, clientError(err.Error()))
+		return
+	}
+
+	if _, err = s.settingsScheduler(settings); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	// The vacation is changed only by the vacation endpoints,
+	// so the stored one is kept whatever the body holds.
+	unlock := s.vacationMu.Lock(name)
+	defer unlock()
+	stored, err := s.settingsRepo.GetSettings(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	settings.Vacation = stored.Vacation
+
+	err = s.settingsRepo.SetSettings(name, settings)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+}
+
+func (s *topicServer) forecastHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+	topicRepo, err := s.topicRepository(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	days := defaultForecastDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		days, err = strconv.Atoi(raw)
+		if err != nil {
+			s.handleError(w, r, clientError(err.Error()))
+			return
+		}
+		if days < 1 || days > maxForecastDays {
+			s.handleError(w, r, clientError(fmt.Sprintf(
+				"days must be from 1 to %d", maxForecastDays)))
+			return
+		}
+	}
+
+	settings, err := s.settingsRepo.GetSettings(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	sched, err := s.settingsScheduler(settings)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	topics, err := topicRepo.GetAllTopics()
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	forecast := stats.Forecast(topics, sched, s.clock.Now(), days)
+	resp := api.ForecastResponse{Days: make([]api.ForecastDay, len(forecast))}
+	for i, d := range forecast {
+		resp.Days[i] = api.ForecastDay{
+			Date:  d.Date.Format(time.DateOnly),
+			Count: d.Count,
+		}
+	}
+
+	data, err := json.Marshal(&resp)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (s *topicServer) getVacationHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+	settings, err := s.settingsRepo.GetSettings(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(settings.Vacation)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (s *topicServer) updateVacationHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.handleError(w, r, clientError(err.Error()))
+		return
+	}
+
+	vacation := new(entity.Vacation)
+	err = json.Unmarshal(data, vacation)
+	if err != nil {
+		s.handleError(w, r, clientError(err.Error()))
+		return
+	}
+	err = scheduler.CheckVacation(vacation)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	unlock := s.vacationMu.Lock(name)
+	defer unlock()
+	settings, err := s.settingsRepo.GetSettings(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	settings.Vacation = vacation
+	err = s.settingsRepo.SetSettings(name, settings)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+}
+
+// deleteVacationHandler cancels a planned vacation
+// or ends the current one right now.
+func (s *topicServer) deleteVacationHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.Context().Value("username").(string)
+	topicRepo, err := s.userTopicRepo.GetUserTopicRepository(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	unlock := s.vacationMu.Lock(name)
+	defer unlock()
+	settings, err := s.settingsRepo.GetSettings(name)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+	if settings.Vacation == nil {
+		return
+	}
+
+	now := s.clock.Now()
+	if now.Before(settings.Vacation.Start) {
+		settings.Vacation = nil
+		err = s.settingsRepo.SetSettings(name, settings)
+	} else {
+		end := settings.Vacation.End
+		if now.Before(end) {
+			end = now
+		}
+		err = s.endVacation(name, topicRepo, settings, end)
+	}
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
 }
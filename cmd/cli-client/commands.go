@@ -0,0 +1,335 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/pkg/client"
+	"github.com/spf13/pflag"
+)
+
+// Exit codes of the subcommands.
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitAuth     = 3
+	exitNotFound = 4
+	exitRejected = 5
+)
+
+// usageError is returned when a subcommand is called wrong.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// command is a subcommand run without the interactive shell.
+type command struct {
+	usage string
+	// noAuth is set for the commands which do not need
+	// the client to be authenticated.
+	noAuth bool
+	run    func(args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"login": {
+			usage:  "login [--reg] [--save-password]",
+			noAuth: true,
+			run:    loginCmd,
+		},
+		"topics": {
+			usage: "topics list [--json] [--due] [--limit n]\n" +
+				"topics show <id> [--json]\n" +
+				"topics add <title>\n" +
+				"topics delete <id>",
+			run: topicsCmd,
+		},
+		"review": {
+			usage: "review <id> [--grade good]",
+			run:   reviewCmd,
+		},
+	}
+}
+
+// runCommand runs the subcommand given by args
+// and returns the exit code of the program.
+func runCommand(args []string) int {
+	if args[0] == "help" {
+		commandsHelp()
+		return exitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+		commandsHelp()
+		return exitUsage
+	}
+
+	var err error
+	if !cmd.noAuth {
+		err = authenticate()
+	}
+	if err == nil {
+		err = cmd.run(args[1:])
+		// A new token, obtained by login or with the saved
+		// password, is kept for the following commands.
+		saveToken()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if _, ok := err.(usageError); ok {
+			fmt.Fprintln(os.Stderr, "Usage:")
+			fmt.Fprintln(os.Stderr, cmd.usage)
+		}
+	}
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	var apiErr *client.APIError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, new(usageError)):
+		return exitUsage
+	case errors.Is(err, errNotLoggedIn), errors.Is(err, client.ErrNotAuthenticated):
+		return exitAuth
+	case errors.As(err, &apiErr):
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			return exitAuth
+		case http.StatusNotFound:
+			return exitNotFound
+		case http.StatusBadRequest, http.StatusConflict:
+			return exitRejected
+		}
+	}
+	return exitError
+}
+
+func commandsHelp() {
+	fmt.Println("Usage:")
+	fmt.Println("\tcli-client [flags]                   run interactive shell")
+	fmt.Println("\tcli-client [flags] <command> [args]  run command")
+	fmt.Println("Flags:")
+	fmt.Print(pflag.CommandLine.FlagUsages())
+	fmt.Println("Commands:")
+	for _, name := range []string{"login", "topics", "review"} {
+		fmt.Println(commands[name].usage)
+	}
+	fmt.Println("Flags can be set with MEMFLOW_* environment variables")
+	fmt.Println("or in a profile of the config file.")
+	fmt.Println("Commands authenticate with the token from " + tokenEnv)
+	fmt.Println("or with the token saved by login, which has to be run")
+	fmt.Println("again when the token expires unless --save-password is given.")
+	fmt.Println("Exit codes:")
+	fmt.Println("\t0 success, 1 error, 2 bad usage, 3 not authenticated,")
+	fmt.Println("\t4 topic not found, 5 request rejected by server")
+}
+
+// newFlagSet returns a flag set which reports errors
+// as usage errors instead of exiting.
+func newFlagSet(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.Usage = func() {}
+	return fs
+}
+
+func parseFlags(fs *pflag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	return nil
+}
+
+// loginCmd asks for the name and the password, checks them
+// and saves the name and the token for the following commands.
+// The password is saved in plain text only with --save-password.
+func loginCmd(args []string) error {
+	fs := newFlagSet("login")
+	reg := fs.Bool("reg", false, "register instead of authenticate")
+	savePassword := fs.Bool("save-password", false,
+		"save the password in plain text to renew the token when it expires")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("login takes no arguments")
+	}
+
+	name := cfg.User
+	var password string
+	var ok bool
+	if name == "" {
+		if name, ok = readLine("name: "); !ok {
+			return errors.New("no name given")
+		}
+	}
+	if password, ok = readLine("password: "); !ok {
+		return errors.New("no password given")
+	}
+
+	err := useTokenFile()
+	if err != nil {
+		return err
+	}
+	if *reg {
+		err = cs.Register(ctx, name, password)
+	} else {
+		err = cs.Auth(ctx, name, password)
+	}
+	if err != nil {
+		return err
+	}
+	creds := &credentials{Name: name}
+	if *savePassword {
+		creds.Password = password
+	}
+	return saveCredentials(creds)
+}
+
+func topicsCmd(args []string) error {
+	if len(args) == 0 {
+		return usageError("no topics command given")
+	}
+	switch args[0] {
+	case "list":
+		return topicsListCmd(args[1:])
+	case "show":
+		return topicsShowCmd(args[1:])
+	case "add":
+		return topicsAddCmd(args[1:])
+	case "delete":
+		return topicsDeleteCmd(args[1:])
+	}
+	return usageError(fmt.Sprintf("unknown topics command %q", args[0]))
+}
+
+func topicsListCmd(args []string) error {
+	fs := newFlagSet("topics list")
+	asJSON := fs.Bool("json", false, "print topics as JSON")
+	due := fs.Bool("due", false, "print only due topics")
+	limit := fs.Int("limit", 0, "print at most n due topics")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("topics list takes no arguments")
+	}
+
+	var topics []client.Topic
+	var err error
+	if *due {
+		topics, err = cs.GetDueTopics(ctx, *limit)
+	} else {
+		topics, err = cs.GetAllTopics(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(topics)
+	}
+	for _, t := range topics {
+		fmt.Printf("%d\t%s\t%s\n", t.Id, t.NextRepeat.Local().Format(time.DateTime), t.Title)
+	}
+	return nil
+}
+
+func topicsShowCmd(args []string) error {
+	fs := newFlagSet("topics show")
+	asJSON := fs.Bool("json", false, "print topic as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := topicId(fs)
+	if err != nil {
+		return err
+	}
+
+	topic, err := cs.GetTopicById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(topic)
+	}
+	printTopic(topic)
+	return nil
+}
+
+func topicsAddCmd(args []string) error {
+	fs := newFlagSet("topics add")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("topics add takes a title")
+	}
+
+	id, err := cs.AddTopic(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Println(id)
+	return nil
+}
+
+func topicsDeleteCmd(args []string) error {
+	fs := newFlagSet("topics delete")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := topicId(fs)
+	if err != nil {
+		return err
+	}
+	return cs.RemoveTopic(ctx, id)
+}
+
+func reviewCmd(args []string) error {
+	fs := newFlagSet("review")
+	rawGrade := fs.String("grade", "good", "0-5 or forgot, again, familiar, hard, good, easy")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := topicId(fs)
+	if err != nil {
+		return err
+	}
+	grade, err := client.ParseGrade(*rawGrade)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	return cs.RepeatTopic(ctx, id, grade)
+}
+
+// topicId returns the only argument left after the flags as a topic id.
+func topicId(fs *pflag.FlagSet) (int, error) {
+	if fs.NArg() != 1 {
+		return 0, usageError("a topic id is required")
+	}
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return 0, usageError(fmt.Sprintf("bad topic id %q", fs.Arg(0)))
+	}
+	return id, nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
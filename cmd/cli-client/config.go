@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultServer  = "http://localhost:8765"
+	defaultProfile = "default"
+)
+
+// cliConfig is the configuration of the client. The values are taken
+// from the flags, the MEMFLOW_* environment variables and the selected
+// profile of the config file, in this order.
+//
+// The config file looks like this:
+//
+//	profile: personal
+//	profiles:
+//	  personal:
+//	    server: http://localhost:8765
+//	    user: alice
+//	  staging:
+//	    server: https://memflow.staging.example.com
+//	    user: alice
+type cliConfig struct {
+	Profile string
+	Server  string
+	User    string
+}
+
+var cfg *cliConfig
+
+// configDir returns the directory of the client files.
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "memflow"), nil
+}
+
+func defineConfigFlags(flags *pflag.FlagSet) {
+	flags.String("config", "", "config file (default ~/.config/memflow/config.yaml)")
+	flags.String("profile", defaultProfile, "config profile")
+	flags.String("server", defaultServer, "server URL")
+	flags.String("user", "", "user name")
+}
+
+// loadConfig reads the config file and selects the profile.
+// The flags have to be parsed already.
+func loadConfig(flags *pflag.FlagSet) (*cliConfig, error) {
+	v := viper.New()
+	v.SetDefault("Profile", defaultProfile)
+	v.SetDefault("Server", defaultServer)
+
+	v.AutomaticEnv()
+	v.SetEnvPrefix("MEMFLOW")
+	for _, key := range []string{"Config", "Profile", "Server", "User"} {
+		if err := v.BindPFlag(key, flags.Lookup(strings.ToLower(key))); err != nil {
+			return nil, err
+		}
+	}
+
+	path := v.GetString("Config")
+	explicit := path != ""
+	if !explicit {
+		dir, err := configDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, "config.yaml")
+	}
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	if err != nil && (explicit || !errors.Is(err, fs.ErrNotExist)) {
+		return nil, err
+	}
+
+	profile := v.GetString("Profile")
+	if err = checkProfile(profile); err != nil {
+		return nil, err
+	}
+	if v.IsSet("Profiles." + profile) {
+		// The profile takes precedence over the top level
+		// of the file but not over the flags and environment.
+		err = v.MergeConfigMap(v.GetStringMap("Profiles." + profile))
+		if err != nil {
+			return nil, err
+		}
+	} else if profile != defaultProfile {
+		return nil, fmt.Errorf("profile %q is not found in %s", profile, path)
+	}
+
+	return &cliConfig{
+		Profile: profile,
+		Server:  v.GetString("Server"),
+		User:    v.GetString("User"),
+	}, nil
+}
+
+// checkProfile returns an error if the profile name cannot name
+// the files of the profile inside the config directory.
+func checkProfile(profile string) error {
+	if profile == "" || profile == "." ||
+		strings.Contains(profile, "..") || strings.ContainsAny(profile, `/\`) {
+		return fmt.Errorf("invalid profile name %q", profile)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Ayaya-zx/mem-flow/pkg/client"
+)
+
+// tokenEnv is the environment variable holding a token which
+// is used instead of the stored credentials.
+const tokenEnv = "MEMFLOW_TOKEN"
+
+// errNotLoggedIn is returned when there is neither a token
+// nor stored credentials.
+var errNotLoggedIn = errors.New("not logged in, run 'login' or set " + tokenEnv)
+
+// credentials are saved by login for the following commands.
+// The password is saved only if the user asked for it, otherwise
+// the commands work until the cached token expires.
+type credentials struct {
+	Name     string `json:"name"`
+	Password string `json:"passwd,omitempty"`
+}
+
+// credentialsPath returns the path of the file which keeps
+// the credentials saved by login for the current profile.
+func credentialsPath() (string, error) {
+	if err := checkProfile(cfg.Profile); err != nil {
+		return "", err
+	}
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials", cfg.Profile+".json"), nil
+}
+
+func loadCredentials() (*credentials, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errNotLoggedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	creds := new(credentials)
+	err = json.Unmarshal(data, creds)
+	if err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// saveCredentials stores the credentials readable by the user only.
+func saveCredentials(creds *credentials) error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// tokenPath returns the path of the file
+// which keeps the token of the current profile.
+func tokenPath() (string, error) {
+	if err := checkProfile(cfg.Profile); err != nil {
+		return "", err
+	}
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tokens", cfg.Profile), nil
+}
+
+// tokens is the file keeping the token between the commands. It is
+// empty when the token is not kept, e.g. if it is taken from tokenEnv.
+var tokens tokenFile
+
+// savedToken is the token loaded from or saved to tokens.
+var savedToken string
+
+// useTokenFile makes the commands keep the token in the token file.
+func useTokenFile() error {
+	path, err := tokenPath()
+	if err != nil {
+		return err
+	}
+	tokens = tokenFile(path)
+	return nil
+}
+
+// saveToken saves the token of the client if it has changed.
+// The token is valid even if it cannot be saved, so the error
+// is ignored and the next command just obtains a new one.
+func saveToken() {
+	token := cs.Token()
+	if tokens == "" || token == "" || token == savedToken {
+		return
+	}
+	if tokens.Save(token) == nil {
+		savedToken = token
+	}
+}
+
+// authenticate sets up the client to use the token from
+// the environment or the saved token. When the saved one
+// is missing or expired, the client obtains a new one if
+// login saved the password.
+func authenticate() error {
+	if token := os.Getenv(tokenEnv); token != "" {
+		cs = client.New(cfg.Server, client.WithToken(token))
+		return nil
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	if err = useTokenFile(); err != nil {
+		return err
+	}
+	savedToken, err = tokens.Load()
+	if err != nil {
+		return err
+	}
+
+	opts := []client.Option{client.WithToken(savedToken)}
+	if creds.Password != "" {
+		opts = append(opts, client.WithCredentials(creds.Name, creds.Password))
+	}
+	cs = client.New(cfg.Server, opts...)
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxBarWidth is the width of the longest bar of the chart.
+const maxBarWidth = 50
+
+func forecast(days int) {
+	forecastDays, err := cs.GetForecast(ctx, days)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	maxCount := 0
+	for _, d := range forecastDays {
+		maxCount = max(maxCount, d.Count)
+	}
+
+	for _, d := range forecastDays {
+		width := 0
+		if maxCount > 0 {
+			width = d.Count * maxBarWidth / maxCount
+		}
+		if d.Count > 0 && width == 0 {
+			width = 1
+		}
+		fmt.Printf("%s |%s %d\n", d.Date, strings.Repeat("#", width), d.Count)
+	}
+}
@@ -2,51 +2,70 @@ package main
 
 import (
 	"bufio"
-	"flag"
+	"context"
 	"fmt"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/Ayaya-zx/mem-flow/internal/auth"
-	"github.com/Ayaya-zx/mem-flow/internal/client"
-	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	"github.com/Ayaya-zx/mem-flow/pkg/client"
+	"github.com/spf13/pflag"
 )
 
-const URL = "http://localhost:8765"
+var cs *client.Client
 
-var cs *client.ClientService
+// ctx is the context of the requests to the server.
+var ctx = context.Background()
+
+// scanner reads the user input.
+var scanner *bufio.Scanner
 
 func main() {
 	var err error
-	var authData auth.AuthData
-	fReg := flag.Bool("reg", false, "Register instead of authenticate")
-	flag.Parse()
+	var name, password string
+	fReg := pflag.Bool("reg", false, "Register instead of authenticate")
+	defineConfigFlags(pflag.CommandLine)
+	// Flags after a command belong to the command.
+	pflag.CommandLine.SetInterspersed(false)
+	pflag.Parse()
 
-	cs = client.NewClientService(URL)
-	scanner := bufio.NewScanner(os.Stdin)
+	cfg, err = loadConfig(pflag.CommandLine)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitUsage)
+	}
+	cs = client.New(cfg.Server)
+	scanner = bufio.NewScanner(os.Stdin)
 
-	fmt.Print("name: ")
-	scanner.Scan()
-	authData.Name = scanner.Text()
-	if err = scanner.Err(); err != nil {
-		fmt.Println(err)
-		return
+	if pflag.NArg() > 0 {
+		os.Exit(runCommand(pflag.Args()))
+	}
+
+	name = cfg.User
+	if name == "" {
+		fmt.Print("name: ")
+		scanner.Scan()
+		name = scanner.Text()
+		if err = scanner.Err(); err != nil {
+			fmt.Println(err)
+			return
+		}
 	}
 
 	fmt.Print("password: ")
 	scanner.Scan()
-	authData.Password = scanner.Text()
+	password = scanner.Text()
 	if err = scanner.Err(); err != nil {
 		fmt.Println(err)
 		return
 	}
 
 	if *fReg {
-		err = cs.Register(authData)
+		err = cs.Register(ctx, name, password)
 	} else {
-		err = cs.Auth(authData)
+		err = cs.Auth(ctx, name, password)
 	}
 	if err != nil {
 		fmt.Println(err)
@@ -71,10 +90,29 @@ func help() {
 	fmt.Println("Usage:")
 	fmt.Println("\thelp    (h)                print this help")
 	fmt.Println("\tlist    (l)                print all topic titles")
+	fmt.Println("\tlist    (l) due [limit]    print topics due now, the most overdue first")
+	fmt.Println("\tboxes   (b)                print topic titles by Leitner boxes")
 	fmt.Println("\tshow    (s) [topic id]     print topic info")
 	fmt.Println("\tadd     (a) [topic title]  add topic")
-	fmt.Println("\trepeat  (r) [topic id]     repeat topic")
+	fmt.Println("\trepeat  (r) [id] [grade]   repeat topic, grade is 0-5 or forgot, again,")
+	fmt.Println("\t                           familiar, hard, good (default), easy")
 	fmt.Println("\tdelete  (d) [topic id]     delete topic")
+	fmt.Println("\tstudy                      repeat due topics one by one")
+	fmt.Println("\tsuspend   [topic id]       exclude topic from repetitions")
+	fmt.Println("\tbury      [topic id]       exclude topic from repetitions until tomorrow")
+	fmt.Println("\tunsuspend [topic id]       return suspended or buried topic")
+	fmt.Println("\tforecast (f) [days]        print number of due topics per day")
+	fmt.Println("\tsettings (o)               print settings")
+	fmt.Println("\tset     [key] [value]      change setting, keys are:")
+	fmt.Println("\t                           scheduler, steps, relearn, ladder, boxes, max,")
+	fmt.Println("\t                           retention, fuzz, balance, weights")
+	fmt.Println("\t                           durations are comma separated, e.g. 20m,1h,")
+	fmt.Println("\t                           weights are printed by fsrs-optimizer")
+	fmt.Println("\tvacation                   print vacation")
+	fmt.Println("\tvacation [start] [end] [mode]")
+	fmt.Println("\t                           plan vacation, dates are YYYY-MM-DD, mode is")
+	fmt.Println("\t                           shift (default) or spread")
+	fmt.Println("\tvacation off               cancel or end vacation")
 }
 
 func shortHelp() {
@@ -83,10 +121,10 @@ func shortHelp() {
 }
 
 func handleCommand(input string) {
-	var cmd, arg string
+	var cmd, arg, arg2, arg3 string
 
 	split := strings.Split(input, " ")
-	if len(split) > 2 {
+	if len(split) > 4 {
 		shortHelp()
 		return
 	}
@@ -96,9 +134,45 @@ func handleCommand(input string) {
 	} else {
 		arg = ""
 	}
+	if len(split) > 2 {
+		arg2 = split[2]
+	}
+	if len(split) > 3 {
+		arg3 = split[3]
+	}
+	if arg2 != "" {
+		switch cmd {
+		case "repeat", "r", "set", "vacation", "list", "l":
+		default:
+			shortHelp()
+			return
+		}
+	}
+	if arg3 != "" && cmd != "vacation" {
+		shortHelp()
+		return
+	}
 	switch cmd {
 	case "list", "l":
-		list()
+		switch {
+		case arg == "":
+			list()
+		case arg == "due":
+			limit := 0
+			if arg2 != "" {
+				var err error
+				limit, err = strconv.Atoi(arg2)
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+			}
+			listDue(limit)
+		default:
+			shortHelp()
+		}
+	case "boxes", "b":
+		boxes()
 	case "add", "a":
 		if arg == "" {
 			shortHelp()
@@ -126,7 +200,26 @@ func handleCommand(input string) {
 			fmt.Println(err)
 			return
 		}
-		repeat(id)
+		grade := client.GradeGood
+		if arg2 != "" {
+			grade, err = client.ParseGrade(arg2)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		repeat(id, grade)
+	case "suspend", "bury", "unsuspend":
+		if arg == "" {
+			shortHelp()
+			return
+		}
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		suspend(cmd, id)
 	case "delete", "d":
 		if arg == "" {
 			shortHelp()
@@ -138,6 +231,38 @@ func handleCommand(input string) {
 			return
 		}
 		remove(id)
+	case "forecast", "f":
+		days := 14
+		if arg != "" {
+			var err error
+			days, err = strconv.Atoi(arg)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		forecast(days)
+	case "settings", "o":
+		showSettings()
+	case "set":
+		if arg == "" || arg2 == "" {
+			shortHelp()
+			return
+		}
+		set(arg, arg2)
+	case "vacation":
+		switch {
+		case arg == "":
+			showVacation()
+		case arg == "off" && arg2 == "":
+			endVacation()
+		case arg2 != "":
+			setVacation(arg, arg2, arg3)
+		default:
+			shortHelp()
+		}
+	case "study":
+		study()
 	case "help", "h":
 		help()
 	case "":
@@ -147,24 +272,65 @@ func handleCommand(input string) {
 }
 
 func list() {
-	topics, err := cs.GetAllTopics()
+	topics, err := cs.GetAllTopics(ctx)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	slices.SortFunc(topics, func(a, b entity.Topic) int {
+	slices.SortFunc(topics, func(a, b client.Topic) int {
 		return a.Id - b.Id
 	})
 
 	fmt.Println("Themes list:")
+	now := time.Now()
 	for _, t := range topics {
-		fmt.Printf("%d: %s\n", t.Id, t.Title)
+		switch {
+		case t.Suspended:
+			fmt.Printf("%d: %s [suspended]\n", t.Id, t.Title)
+		case !t.Active(now):
+			fmt.Printf("%d: %s [buried]\n", t.Id, t.Title)
+		default:
+			fmt.Printf("%d: %s\n", t.Id, t.Title)
+		}
+	}
+}
+
+func listDue(limit int) {
+	topics, err := cs.GetDueTopics(ctx, limit)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(topics) == 0 {
+		fmt.Println("Nothing to repeat")
+		return
+	}
+
+	fmt.Println("Due topics:")
+	now := time.Now()
+	for _, t := range topics {
+		fmt.Printf("%d: %s (overdue %s)\n", t.Id, t.Title, now.Sub(t.NextRepeat).Round(time.Minute))
+	}
+}
+
+func boxes() {
+	groups, err := cs.GetTopicsByBox(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, g := range groups {
+		fmt.Printf("Box %d:\n", g.Box)
+		for _, t := range g.Topics {
+			fmt.Printf("\t%d: %s\n", t.Id, t.Title)
+		}
 	}
 }
 
 func add(title string) {
-	err := cs.AddTopic(title)
+	_, err := cs.AddTopic(ctx, title)
 	if err != nil {
 		fmt.Println(err)
 	} else {
@@ -173,21 +339,50 @@ func add(title string) {
 }
 
 func show(id int) {
-	topic, err := cs.GetTopicById(id)
+	topic, err := cs.GetTopicById(ctx, id)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
+	printTopic(topic)
+}
 
+func printTopic(topic *client.Topic) {
 	fmt.Println("Id:", topic.Id)
 	fmt.Println("Title:", topic.Title)
 	fmt.Println("Created:", topic.Created)
 	fmt.Println("Last repeated:", topic.LastRepeated)
 	fmt.Println("Next repeat:", topic.NextRepeat)
+	fmt.Println("Stage:", topic.State.Stage())
+	fmt.Println("Interval:", topic.State.Interval)
+	fmt.Println("Lapses:", topic.State.Lapses)
+	if topic.Suspended {
+		fmt.Println("Suspended")
+	}
+	if !topic.BuriedUntil.IsZero() {
+		fmt.Println("Buried until:", topic.BuriedUntil)
+	}
 }
 
-func repeat(id int) {
-	err := cs.RepeatTopic(id)
+func repeat(id int, grade client.Grade) {
+	err := cs.RepeatTopic(ctx, id, grade)
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println("OK")
+	}
+}
+
+func suspend(cmd string, id int) {
+	var err error
+	switch cmd {
+	case "suspend":
+		err = cs.SuspendTopic(ctx, id)
+	case "bury":
+		err = cs.BuryTopic(ctx, id)
+	default:
+		err = cs.UnsuspendTopic(ctx, id)
+	}
 	if err != nil {
 		fmt.Println(err)
 	} else {
@@ -196,7 +391,7 @@ func repeat(id int) {
 }
 
 func remove(id int) {
-	err := cs.RemoveTopic(id)
+	err := cs.RemoveTopic(ctx, id)
 	if err != nil {
 		fmt.Println(err)
 	} else {
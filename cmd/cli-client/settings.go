@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Ayaya-zx/mem-flow/pkg/client"
+)
+
+func showSettings() {
+	settings, err := cs.GetSettings(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	scheduler := settings.Scheduler
+	if scheduler == "" {
+		scheduler = "server default"
+	}
+	fmt.Println("Scheduler:", scheduler)
+	fmt.Println("Learning steps:", formatDurations(settings.LearningSteps))
+	fmt.Println("Relearning steps:", formatDurations(settings.RelearningSteps))
+	fmt.Println("Ladder intervals:", formatDurations(settings.GraduatingIntervals))
+	fmt.Println("Leitner boxes:", formatDurations(settings.LeitnerIntervals))
+	if settings.MaxInterval == 0 {
+		fmt.Println("Max interval: none")
+	} else {
+		fmt.Println("Max interval:", settings.MaxInterval)
+	}
+	fmt.Println("Fuzz:", !settings.DisableFuzz)
+	fmt.Println("Load balance:", settings.LoadBalance)
+	if settings.RetentionTarget == 0 {
+		fmt.Println("Retention target: default")
+	} else {
+		fmt.Println("Retention target:", settings.RetentionTarget)
+	}
+	fmt.Println("FSRS weights:", formatWeights(settings.FSRSWeights))
+}
+
+func set(key, value string) {
+	settings, err := cs.GetSettings(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	switch key {
+	case "scheduler":
+		settings.Scheduler = value
+	case "steps":
+		settings.LearningSteps, err = parseDurations(value)
+	case "relearn":
+		settings.RelearningSteps, err = parseDurations(value)
+	case "ladder":
+		settings.GraduatingIntervals, err = parseDurations(value)
+	case "boxes":
+		settings.LeitnerIntervals, err = parseDurations(value)
+	case "max":
+		settings.MaxInterval, err = client.ParseDuration(value)
+	case "fuzz":
+		var fuzz bool
+		fuzz, err = strconv.ParseBool(value)
+		settings.DisableFuzz = !fuzz
+	case "balance":
+		settings.LoadBalance, err = strconv.ParseBool(value)
+	case "retention":
+		settings.RetentionTarget, err = strconv.ParseFloat(value, 64)
+	case "weights":
+		settings.FSRSWeights, err = parseWeights(value)
+	default:
+		fmt.Println("Unknown setting")
+		return
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	err = cs.UpdateSettings(ctx, settings)
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println("OK")
+	}
+}
+
+func parseDurations(s string) ([]client.Duration, error) {
+	if s == "default" {
+		return nil, nil
+	}
+	var res []client.Duration
+	for _, raw := range strings.Split(s, ",") {
+		d, err := client.ParseDuration(raw)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+func formatDurations(ds []client.Duration) string {
+	if len(ds) == 0 {
+		return "default"
+	}
+	strs := make([]string, len(ds))
+	for i, d := range ds {
+		strs[i] = d.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// parseWeights parses comma separated FSRS weights
+// as printed by fsrs-optimizer.
+func parseWeights(s string) ([]float64, error) {
+	if s == "default" {
+		return nil, nil
+	}
+	var res []float64
+	for _, raw := range strings.Split(s, ",") {
+		w, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, w)
+	}
+	return res, nil
+}
+
+func formatWeights(ws []float64) string {
+	if len(ws) == 0 {
+		return "default"
+	}
+	strs := make([]string, len(ws))
+	for i, w := range ws {
+		strs[i] = strconv.FormatFloat(w, 'g', -1, 64)
+	}
+	return strings.Join(strs, ",")
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/pkg/client"
+)
+
+// study repeats the due topics one by one. For every topic it waits
+// until the user recalls it, reveals what is known about the topic,
+// asks for a grade and submits the review. When the queue runs out
+// the due topics are fetched again, so topics which fell due during
+// the session, such as forgotten ones back from relearning, are
+// repeated too. Typing 'q' at any prompt stops the session.
+func study() {
+	topics, err := cs.GetDueTopics(ctx, 0)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(topics) == 0 {
+		fmt.Println("Nothing to repeat")
+		return
+	}
+
+	fmt.Printf("%d topics to repeat, type 'q' to stop\n", len(topics))
+	start := time.Now()
+	reviewed, failed := 0, 0
+	total := len(topics)
+	// broken holds the topics whose review could not be submitted,
+	// so they are not asked again and again.
+	broken := make(map[int]bool)
+	for i := 0; ; i++ {
+		if len(topics) == 0 {
+			topics, err = cs.GetDueTopics(ctx, 0)
+			if err != nil {
+				fmt.Println(err)
+				break
+			}
+			topics = slices.DeleteFunc(topics, func(t client.Topic) bool {
+				return broken[t.Id]
+			})
+			if len(topics) == 0 {
+				break
+			}
+			total += len(topics)
+		}
+		t := topics[0]
+		topics = topics[1:]
+
+		fmt.Printf("\n[%d/%d] %s\n", i+1, total, t.Title)
+		if _, ok := prompt("Press Enter to reveal "); !ok {
+			break
+		}
+		reveal(t)
+
+		grade, ok := askGrade()
+		if !ok {
+			break
+		}
+		err = cs.RepeatTopic(ctx, t.Id, grade)
+		if err != nil {
+			fmt.Println(err)
+			broken[t.Id] = true
+			continue
+		}
+		reviewed++
+		if !grade.Passed() {
+			failed++
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Reviewed:", reviewed)
+	fmt.Println("Failed:", failed)
+	fmt.Println("Time spent:", time.Since(start).Round(time.Second))
+}
+
+// reveal prints what helps the user to judge the recall.
+func reveal(t client.Topic) {
+	fmt.Println("Stage:", t.State.Stage())
+	fmt.Println("Last repeated:", t.LastRepeated.Local().Format(time.DateTime))
+	fmt.Println("Interval:", t.NextRepeat.Sub(t.LastRepeated).Round(time.Minute))
+	fmt.Println("Lapses:", t.State.Lapses)
+}
+
+// askGrade asks for a grade until the user types a valid one.
+// An empty input means good. It reports false if the user
+// stopped the session.
+func askGrade() (client.Grade, bool) {
+	for {
+		input, ok := prompt("Grade (0-5, forgot, again, familiar, hard, good, easy) [good]: ")
+		if !ok {
+			return 0, false
+		}
+		if input == "" {
+			return client.GradeGood, true
+		}
+		grade, err := client.ParseGrade(input)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return grade, true
+	}
+}
+
+// prompt prints the message and reads a line of input. It reports
+// false if the input has ended or the user typed 'q'.
+func prompt(msg string) (string, bool) {
+	input, ok := readLine(msg)
+	input = strings.TrimSpace(input)
+	return input, ok && input != "q"
+}
+
+// readLine prints the message and reads a line of input.
+// It reports false if the input has ended.
+func readLine(msg string) (string, bool) {
+	fmt.Print(msg)
+	if !scanner.Scan() {
+		return "", false
+	}
+	return scanner.Text(), true
+}
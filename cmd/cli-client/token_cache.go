@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tokenFile keeps the token between runs of the client in the file
+// with the given path. The file is readable by its owner only.
+type tokenFile string
+
+// Load returns the saved token or an empty string if there is none.
+func (c tokenFile) Load() (string, error) {
+	data, err := os.ReadFile(string(c))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save replaces the saved token.
+func (c tokenFile) Save(token string) error {
+	dir := filepath.Dir(string(c))
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	// The temporary file is created with 0600 permissions
+	// and replaces the old one at once, so the token is never
+	// readable by others even if the old file was.
+	f, err := os.CreateTemp(dir, filepath.Base(string(c))+".*")
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(token)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), string(c))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/pkg/client"
+)
+
+func showVacation() {
+	v, err := cs.GetVacation(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if v == nil {
+		fmt.Println("No vacation planned")
+		return
+	}
+
+	fmt.Println("Start:", v.Start.Local().Format(time.DateOnly))
+	fmt.Println("End:  ", v.End.Local().Format(time.DateOnly))
+	fmt.Println("Mode: ", v.Mode)
+	if v.Mode == client.VacationSpread {
+		fmt.Println("Spread over", v.SpreadDays, "days")
+	}
+}
+
+// setVacation plans a vacation from the beginning of the start
+// day till the beginning of the end day in local time.
+func setVacation(start, end, mode string) {
+	v := &client.Vacation{Mode: mode}
+	var err error
+	v.Start, err = time.ParseInLocation(time.DateOnly, start, time.Local)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	v.End, err = time.ParseInLocation(time.DateOnly, end, time.Local)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	err = cs.SetVacation(ctx, v)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("Vacation planned")
+}
+
+func endVacation() {
+	err := cs.EndVacation(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("Vacation ended")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Ayaya-zx/mem-flow/internal/scheduler"
+)
+
+// fsrs-optimizer reads a JSON array of reviews, as returned
+// by GET /reviews, and prints FSRS weights fitted to them
+// separated by commas. They are set with the CLI command
+// "set weights", which keeps the other settings.
+func main() {
+	fInput := flag.String("i", "", "File with reviews (default stdin)")
+	flag.Parse()
+
+	var err error
+	var data []byte
+	if *fInput == "" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*fInput)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var reviews []scheduler.FSRSReview
+	err = json.Unmarshal(data, &reviews)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	weights, loss, err := scheduler.OptimizeFSRS(reviews, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "log loss: %.4f\n", loss)
+
+	strs := make([]string, len(weights))
+	for i, w := range weights {
+		strs[i] = strconv.FormatFloat(w, 'g', -1, 64)
+	}
+	fmt.Println(strings.Join(strs, ","))
+}
@@ -1,5 +1,11 @@
 package api
 
+import "github.com/Ayaya-zx/mem-flow/internal/entity"
+
 type CreateTopicRequest struct {
 	Title string
 }
+
+type RepeatTopicRequest struct {
+	Grade *entity.Grade `json:"grade"`
+}
@@ -1,5 +1,41 @@
 package api
 
+import "github.com/Ayaya-zx/mem-flow/internal/entity"
+
+// Codes of the errors returned in ErrorResponse.
+const (
+	ErrorBadRequest     = "bad_request"
+	ErrorUnauthorized   = "unauthorized"
+	ErrorTopicNotFound  = "topic_not_found"
+	ErrorTopicSuspended = "topic_suspended"
+	ErrorTitleConflict  = "title_conflict"
+	ErrorUserExists     = "user_exists"
+	ErrorInternal       = "internal"
+)
+
+// ErrorResponse is the body of every error response.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 type CreateTopicResponse struct {
 	Id int `json:"id"`
 }
+
+// TopicBoxGroup is a group of topics from the same Leitner box.
+type TopicBoxGroup struct {
+	Box    int             `json:"box"`
+	Topics []*entity.Topic `json:"topics"`
+}
+
+type ForecastResponse struct {
+	Days []ForecastDay `json:"days"`
+}
+
+// ForecastDay is the number of topics due on the date
+// formatted as YYYY-MM-DD.
+type ForecastDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,52 @@
+// Package clock abstracts the current time so that
+// scheduling can be tested without waiting.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the clock of the system.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is a clock that stands still until it is moved.
+// It is safe for concurent use by multiple goroutines.
+type Fake struct {
+	m   sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a fake clock showing the given time.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.now
+}
+
+// Set moves the clock to the given time.
+func (f *Fake) Set(now time.Time) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.now = now
+}
+
+// Advance moves the clock forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.now = f.now.Add(d)
+}
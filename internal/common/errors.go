@@ -7,15 +7,23 @@ type (
 	UserAlreadyExistsError                string
 	EmptyUserName                         string
 	TopicTitleError                       string
+	TopicTitleConflictError               string
 	TopicNotExistsError                   string
 	InvalidAuthData                       string
 	InvalidToken                          string
+	UnknownSchedulerError                 string
+	InvalidSettingsError                  string
+	TopicSuspendedError                   string
 )
 
 func (e TopicTitleError) Error() string {
 	return string(e)
 }
 
+func (e TopicTitleConflictError) Error() string {
+	return string(e)
+}
+
 func (e TopicNotExistsError) Error() string {
 	return string(e)
 }
@@ -47,3 +55,15 @@ func (e InvalidAuthData) Error() string {
 func (e InvalidToken) Error() string {
 	return string(e)
 }
+
+func (e UnknownSchedulerError) Error() string {
+	return string(e)
+}
+
+func (e InvalidSettingsError) Error() string {
+	return string(e)
+}
+
+func (e TopicSuspendedError) Error() string {
+	return string(e)
+}
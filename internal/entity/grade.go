@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Grade is the quality of a recall on the SuperMemo scale from 0 to 5.
+// Grades below GradeHard mean the topic was not remembered.
+type Grade int
+
+const (
+	// Complete blackout.
+	GradeBlackout Grade = iota
+	// Incorrect response, the correct one remembered on seeing it.
+	GradeWrong
+	// Incorrect response, the correct one seemed easy to recall.
+	GradeFamiliar
+	// Correct response recalled with serious difficulty.
+	GradeHard
+	// Correct response after a hesitation.
+	GradeGood
+	// Perfect response.
+	GradeEasy
+)
+
+// Valid reports whether g is within the 0–5 scale.
+func (g Grade) Valid() bool {
+	return g >= GradeBlackout && g <= GradeEasy
+}
+
+// Passed reports whether the topic was remembered.
+func (g Grade) Passed() bool {
+	return g >= GradeHard
+}
+
+// gradeNames are the names of the grades in the order of their values.
+var gradeNames = [...]string{"forgot", "again", "familiar", "hard", "good", "easy"}
+
+// String returns the name of the grade or its number
+// if it is out of the scale.
+func (g Grade) String() string {
+	if !g.Valid() {
+		return strconv.Itoa(int(g))
+	}
+	return gradeNames[g]
+}
+
+// ParseGrade parses a grade given either as a number from 0 to 5
+// or as one of the names: forgot, again, familiar, hard, good and easy.
+func ParseGrade(s string) (Grade, error) {
+	for g, name := range gradeNames {
+		if s == name {
+			return Grade(g), nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || !Grade(n).Valid() {
+		return 0, fmt.Errorf("invalid grade %q", s)
+	}
+	return Grade(n), nil
+}
+
+// UnmarshalJSON accepts a grade given either as a number
+// or as a string understood by ParseGrade.
+func (g *Grade) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		if !Grade(n).Valid() {
+			return fmt.Errorf("invalid grade %d", n)
+		}
+		*g = Grade(n)
+		return nil
+	}
+	parsed, err := ParseGrade(s)
+	if err != nil {
+		return err
+	}
+	*g = parsed
+	return nil
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseGrade(t *testing.T) {
+	var tests = []struct {
+		in      string
+		want    Grade
+		wantErr bool
+	}{
+		{"forgot", GradeBlackout, false},
+		{"again", GradeWrong, false},
+		{"familiar", GradeFamiliar, false},
+		{"good", GradeGood, false},
+		{"3", GradeHard, false},
+		{"6", 0, true},
+		{"meh", 0, true},
+	}
+
+	for _, test := range tests {
+		g, err := ParseGrade(test.in)
+		if (err != nil) != test.wantErr {
+			t.Errorf("on %q got error %v; want error %t", test.in, err, test.wantErr)
+			continue
+		}
+		if g != test.want {
+			t.Errorf("on %q got %d; want %d", test.in, g, test.want)
+		}
+	}
+}
+
+func TestGradeUnmarshalJSON(t *testing.T) {
+	var req struct {
+		Grade Grade `json:"grade"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"grade":"forgot"}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Grade != GradeBlackout {
+		t.Errorf("got %d; want %d", req.Grade, GradeBlackout)
+	}
+	if err := json.Unmarshal([]byte(`{"grade":5}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Grade != GradeEasy {
+		t.Errorf("got %d; want %d", req.Grade, GradeEasy)
+	}
+}
+
+func TestGradeUnmarshalJSONOutOfScale(t *testing.T) {
+	var g Grade
+	for _, data := range []string{`6`, `-1`, `"6"`} {
+		if err := json.Unmarshal([]byte(data), &g); err == nil {
+			t.Errorf("on %s got nil; want error", data)
+		}
+	}
+}
+
+func TestGradeString(t *testing.T) {
+	for g := GradeBlackout; g <= GradeEasy; g++ {
+		parsed, err := ParseGrade(g.String())
+		if err != nil || parsed != g {
+			t.Errorf("got %d, %v parsing %q; want %d", parsed, err, g.String(), g)
+		}
+	}
+	if got := Grade(7).String(); got != "7" {
+		t.Errorf("got %q; want \"7\"", got)
+	}
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// ReviewEvent is a record of a single topic repetition.
+type ReviewEvent struct {
+	TopicId int       `json:"topicId"`
+	Time    time.Time `json:"time"`
+	Grade   Grade     `json:"grade"`
+	// PrevInterval is the interval the topic was scheduled for
+	// before the repetition.
+	PrevInterval time.Duration `json:"prevInterval"`
+	// NewInterval is the interval chosen by the scheduler.
+	NewInterval time.Duration `json:"newInterval"`
+	// Elapsed is the time passed since the previous repetition.
+	Elapsed time.Duration `json:"elapsed"`
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReviewStateVersion is the version of the ReviewState layout.
+// It has to be increased whenever the meaning of the stored
+// fields changes, and UnmarshalJSON has to upgrade older states.
+const ReviewStateVersion = 1
+
+// Stages of a topic as returned by ReviewState.Stage.
+const (
+	StageNew        = "new"
+	StageLearning   = "learning"
+	StageRelearning = "relearning"
+	StageReview     = "review"
+)
+
+type Level int
+
+// ReviewState holds the scheduling progress of a topic.
+type ReviewState struct {
+	Version int `json:"version"`
+	// Step is the current learning or relearning step starting
+	// from 1. Zero means the topic has graduated from the steps.
+	Step int `json:"step"`
+	// Relearning is set while a forgotten topic goes
+	// through the relearning steps.
+	Relearning bool `json:"relearning"`
+	// Lapses is the number of times the topic was forgotten.
+	Lapses int `json:"lapses"`
+	// Level is the step of the ladder scheduler.
+	Level Level `json:"level"`
+	// Interval is the last interval the topic was scheduled for.
+	Interval time.Duration `json:"interval"`
+	// EaseFactor and Repetitions are used by the SM-2 scheduler.
+	EaseFactor  float64 `json:"easeFactor"`
+	Repetitions int     `json:"repetitions"`
+	// Stability and Difficulty are used by the FSRS scheduler.
+	Stability  float64 `json:"stability"`
+	Difficulty float64 `json:"difficulty"`
+	// Box is the box of the Leitner scheduler starting from 1.
+	// Zero means the topic has not been put into a box yet.
+	Box int `json:"box"`
+}
+
+// Stage returns the stage of the topic: new if it has never
+// been repeated, relearning after it was forgotten, learning
+// while its interval is shorter than a day, and review afterwards.
+func (s ReviewState) Stage() string {
+	switch {
+	case s.Interval == 0:
+		return StageNew
+	case s.Relearning:
+		return StageRelearning
+	case s.Interval < 24*time.Hour:
+		return StageLearning
+	default:
+		return StageReview
+	}
+}
+
+func (s ReviewState) MarshalJSON() ([]byte, error) {
+	type state ReviewState
+	st := state(s)
+	st.Version = ReviewStateVersion
+	return json.Marshal(st)
+}
+
+func (s *ReviewState) UnmarshalJSON(data []byte) error {
+	type state ReviewState
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return err
+	}
+	// Version 0 means a state written before versioning was
+	// introduced. It has the same layout as version 1.
+	if st.Version > ReviewStateVersion {
+		return fmt.Errorf("unsupported review state version %d", st.Version)
+	}
+	st.Version = ReviewStateVersion
+	*s = ReviewState(st)
+	return nil
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestReviewStateRoundTrip(t *testing.T) {
+	topic := NewTopic(1, "MyTopic", time.Now(), nil)
+	topic.State = ReviewState{
+		Level:       3,
+		Interval:    48 * time.Hour,
+		EaseFactor:  2.36,
+		Repetitions: 4,
+		Stability:   12.5,
+		Difficulty:  5.1,
+	}
+
+	data, err := json.Marshal(topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Topic
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := topic.State
+	want.Version = ReviewStateVersion
+	if got.State != want {
+		t.Errorf("got state %+v; want %+v", got.State, want)
+	}
+}
+
+func TestReviewStateVersion(t *testing.T) {
+	var s ReviewState
+
+	// States without a version are upgraded.
+	if err := json.Unmarshal([]byte(`{"level":2}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != ReviewStateVersion || s.Level != 2 {
+		t.Errorf("got state %+v; want version %d and level 2", s, ReviewStateVersion)
+	}
+
+	// States from the future are rejected.
+	if err := json.Unmarshal([]byte(`{"version":100}`), &s); err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
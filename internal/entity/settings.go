@@ -0,0 +1,57 @@
+package entity
+
+import "slices"
+
+// Settings holds per-user preferences.
+type Settings struct {
+	// Scheduler is the name of the scheduling algorithm.
+	// Empty string means the server default.
+	Scheduler string `json:"scheduler"`
+	// RetentionTarget is the probability of recall the FSRS
+	// scheduler aims for. Zero means the default one.
+	RetentionTarget float64 `json:"retentionTarget"`
+	// FSRSWeights are the parameters of the FSRS model.
+	// Empty means the default ones.
+	FSRSWeights []float64 `json:"fsrsWeights"`
+	// LeitnerIntervals are the review frequencies of the boxes
+	// of the Leitner scheduler, one per box. Empty means the
+	// default ones.
+	LeitnerIntervals []Duration `json:"leitnerIntervals"`
+	// LearningSteps are the intervals a new topic goes through
+	// before the scheduler takes it over. Empty means the default.
+	LearningSteps []Duration `json:"learningSteps"`
+	// RelearningSteps are the intervals a forgotten topic goes
+	// through before the scheduler takes it back. Empty means the
+	// default. The Leitner scheduler relearns topics in its first box.
+	RelearningSteps []Duration `json:"relearningSteps"`
+	// GraduatingIntervals are the intervals of the ladder
+	// scheduler. Empty means the default ones.
+	GraduatingIntervals []Duration `json:"graduatingIntervals"`
+	// MaxInterval limits intervals of all schedulers.
+	// Zero means no limit.
+	MaxInterval Duration `json:"maxInterval"`
+	// DisableFuzz turns off the random change of intervals.
+	DisableFuzz bool `json:"disableFuzz"`
+	// LoadBalance moves due dates to the days with less
+	// topics within the fuzz range.
+	LoadBalance bool `json:"loadBalance"`
+	// Vacation is the planned vacation of the user, if any.
+	// It is changed only by the vacation endpoints
+	// and removed once the user returns.
+	Vacation *Vacation `json:"vacation,omitempty"`
+}
+
+// Clone returns a deep copy of the settings.
+func (s *Settings) Clone() *Settings {
+	c := *s
+	c.FSRSWeights = slices.Clone(s.FSRSWeights)
+	c.LeitnerIntervals = slices.Clone(s.LeitnerIntervals)
+	c.LearningSteps = slices.Clone(s.LearningSteps)
+	c.RelearningSteps = slices.Clone(s.RelearningSteps)
+	c.GraduatingIntervals = slices.Clone(s.GraduatingIntervals)
+	if s.Vacation != nil {
+		v := *s.Vacation
+		c.Vacation = &v
+	}
+	return &c
+}
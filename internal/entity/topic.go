@@ -4,43 +4,66 @@ import (
 	"time"
 )
 
-type Level int
-
 type Topic struct {
-	Id           int       `json:"id"`
-	Title        string    `json:"title"`
-	Created      time.Time `json:"created"`
-	LastRepeated time.Time `json:"lastRepeated"`
-	NextRepeat   time.Time `json:"nextRepeat"`
-	level        int
+	Id           int         `json:"id"`
+	Title        string      `json:"title"`
+	Created      time.Time   `json:"created"`
+	LastRepeated time.Time   `json:"lastRepeated"`
+	NextRepeat   time.Time   `json:"nextRepeat"`
+	State        ReviewState `json:"state"`
+	// Suspended topics are not repeated until they are unsuspended.
+	Suspended bool `json:"suspended"`
+	// BuriedUntil is the time until which the topic is not repeated.
+	BuriedUntil time.Time `json:"buriedUntil"`
+}
+
+// Scheduler decides when a topic has to be repeated next.
+type Scheduler interface {
+	// Init returns the review state of a topic created at now
+	// and the time of its first repetition.
+	Init(now time.Time) (ReviewState, time.Time)
+	// Schedule is called when the topic is repeated at now
+	// and recalled with the grade g. It returns the new review
+	// state of the topic and the time of the next repetition.
+	Schedule(t Topic, g Grade, now time.Time) (ReviewState, time.Time)
 }
 
-func NewTopic(id int, title string) *Topic {
-	return &Topic{
+// NewTopic returns a topic created at now whose first repetition
+// is chosen by s. Without a scheduler the topic is due at once.
+func NewTopic(id int, title string, now time.Time, s Scheduler) *Topic {
+	t := &Topic{
 		Id:           id,
 		Title:        title,
-		Created:      time.Now(),
-		LastRepeated: time.Now(),
-		NextRepeat:   time.Now().Add(20 * time.Minute),
-		level:        0,
+		Created:      now,
+		LastRepeated: now,
+		NextRepeat:   now,
+		State:        ReviewState{Version: ReviewStateVersion},
+	}
+	if s != nil {
+		t.State, t.NextRepeat = s.Init(now)
 	}
+	return t
 }
 
-func (t *Topic) Repeat() {
-	t.LastRepeated = time.Now()
-	switch t.level {
-	case 0: // 8 hours
-		t.NextRepeat = time.Now().Add(time.Duration(8 * time.Hour))
-	case 1: // 24 hours
-		t.NextRepeat = time.Now().Add(time.Duration(24 * time.Hour))
-	case 2: // 2 days
-		t.NextRepeat = time.Now().Add(time.Duration(2 * 24 * time.Hour))
-	case 3: // 1 week
-		t.NextRepeat = time.Now().Add(time.Duration(7 * 24 * time.Hour))
-	default: // 1 month
-		t.NextRepeat = time.Now().Add(time.Duration(30 * 24 * time.Hour))
-	}
-	if t.level < 4 {
-		t.level++
+// Repeat marks the topic as repeated at now with the grade g
+// and asks the scheduler when it has to be repeated next.
+// It returns the record of the repetition.
+func (t *Topic) Repeat(s Scheduler, g Grade, now time.Time) *ReviewEvent {
+	e := &ReviewEvent{
+		TopicId:      t.Id,
+		Time:         now,
+		Grade:        g,
+		PrevInterval: t.NextRepeat.Sub(t.LastRepeated),
+		Elapsed:      now.Sub(t.LastRepeated),
 	}
+	t.State, t.NextRepeat = s.Schedule(*t, g, now)
+	t.LastRepeated = now
+	e.NewInterval = t.NextRepeat.Sub(now)
+	return e
+}
+
+// Active reports whether the topic can be repeated at now,
+// that is it is neither suspended nor buried.
+func (t *Topic) Active(now time.Time) bool {
+	return !t.Suspended && !now.Before(t.BuriedUntil)
 }
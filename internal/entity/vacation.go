@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Ways to deal with topics which fall due during a vacation.
+const (
+	// VacationShift moves all topics due after the start of the
+	// vacation forward by its length.
+	VacationShift = "shift"
+	// VacationSpread spreads topics due during the vacation
+	// over several days after it ends.
+	VacationSpread = "spread"
+)
+
+// Vacation is a period when the user does not repeat topics.
+type Vacation struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Mode is either VacationShift or VacationSpread.
+	Mode string `json:"mode"`
+	// SpreadDays is the number of days the topics
+	// are spread over in the VacationSpread mode.
+	SpreadDays int `json:"spreadDays"`
+}
@@ -0,0 +1,61 @@
+package boltdb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/repotest"
+	bolt "go.etcd.io/bbolt"
+)
+
+// openTestDB opens an empty database which is closed
+// at the end of the test.
+func openTestDB(t *testing.T) *bolt.DB {
+	db, err := Open(filepath.Join(t.TempDir(), "memflow.bolt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestBoltTopicRepositoryContract(t *testing.T) {
+	repotest.TestTopicRepository(t, func(t *testing.T, clk clock.Clock) repo.TopicRepository {
+		r, err := NewBoltUserTopicRepository(openTestDB(t), clk).GetUserTopicRepository("user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	})
+}
+
+func TestBoltUserRepositoryContract(t *testing.T) {
+	repotest.TestUserRepository(t, func(t *testing.T) repo.UserRepository {
+		return NewBoltUserRepository(openTestDB(t))
+	})
+}
+
+func TestBoltSettingsRepositoryContract(t *testing.T) {
+	repotest.TestSettingsRepository(t, func(t *testing.T) repo.SettingsRepository {
+		return NewBoltSettingsRepository(openTestDB(t))
+	})
+}
+
+func TestBoltReviewRepositoryContract(t *testing.T) {
+	repotest.TestReviewRepository(t, func(t *testing.T) repo.ReviewRepository {
+		return NewBoltReviewRepository(openTestDB(t))
+	})
+}
+
+func TestBoltUserTopicRepositoryContract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memflow.bolt")
+	repotest.TestUserTopicRepository(t, func(t *testing.T, clk clock.Clock) (repo.UserTopicRepository, func()) {
+		db, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewBoltUserTopicRepository(db, clk), func() { db.Close() }
+	})
+}
@@ -0,0 +1,103 @@
+package boltdb
+
+import (
+	"encoding/json"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltReviewRepository is a bbolt implementation of review
+// repository. Reviews of a user are kept in their bucket under
+// sequence numbers, so they are in chronological order.
+// It is safe for concurent use by multiple goroutines.
+type BoltReviewRepository struct {
+	db *bolt.DB
+}
+
+func NewBoltReviewRepository(db *bolt.DB) *BoltReviewRepository {
+	return &BoltReviewRepository{db: db}
+}
+
+func (r *BoltReviewRepository) AddReview(name string, e *entity.ReviewEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(reviewsBucket).CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return err
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(itob(seq), data)
+	})
+}
+
+func (r *BoltReviewRepository) GetReviews(name string) ([]*entity.ReviewEvent, error) {
+	return r.queryReviews(name, func(*entity.ReviewEvent) bool { return true })
+}
+
+func (r *BoltReviewRepository) GetTopicReviews(name string, topicId int) ([]*entity.ReviewEvent, error) {
+	return r.queryReviews(name, func(e *entity.ReviewEvent) bool {
+		return e.TopicId == topicId
+	})
+}
+
+func (r *BoltReviewRepository) RemoveTopicReviews(name string, topicId int) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(reviewsBucket).Bucket([]byte(name))
+		if b == nil {
+			return nil
+		}
+		// Deleting while iterating with a cursor
+		// skips keys, so the keys are collected first.
+		var keys [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var e entity.ReviewEvent
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if e.TopicId == topicId {
+				keys = append(keys, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err = b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *BoltReviewRepository) queryReviews(name string, match func(*entity.ReviewEvent) bool) ([]*entity.ReviewEvent, error) {
+	res := make([]*entity.ReviewEvent, 0)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(reviewsBucket).Bucket([]byte(name))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			e := new(entity.ReviewEvent)
+			if err := json.Unmarshal(v, e); err != nil {
+				return err
+			}
+			if match(e) {
+				res = append(res, e)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
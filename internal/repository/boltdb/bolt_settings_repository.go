@@ -0,0 +1,44 @@
+package boltdb
+
+import (
+	"encoding/json"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltSettingsRepository is a bbolt implementation
+// of settings repository. It is safe for concurent use
+// by multiple goroutines.
+type BoltSettingsRepository struct {
+	db *bolt.DB
+}
+
+func NewBoltSettingsRepository(db *bolt.DB) *BoltSettingsRepository {
+	return &BoltSettingsRepository{db: db}
+}
+
+func (r *BoltSettingsRepository) GetSettings(name string) (*entity.Settings, error) {
+	s := new(entity.Settings)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(settingsBucket).Get([]byte(name))
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *BoltSettingsRepository) SetSettings(name string, s *entity.Settings) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(settingsBucket).Put([]byte(name), data)
+	})
+}
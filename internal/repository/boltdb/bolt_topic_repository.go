@@ -0,0 +1,234 @@
+package boltdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltTopicRepository is a bbolt implementation of topics repository.
+// The topics are kept in the bucket of the user together with the
+// indexes by title and by the next repetition time, which are updated
+// in the same transactions. It is safe for concurent use by multiple
+// goroutines.
+type BoltTopicRepository struct {
+	db    *bolt.DB
+	name  []byte
+	clock clock.Clock
+}
+
+func (ts *BoltTopicRepository) AddTopic(title string, s entity.Scheduler) (int, error) {
+	if title == "" {
+		return 0, common.TopicTitleError("topic's title is empty")
+	}
+
+	var id int
+	err := ts.db.Update(func(tx *bolt.Tx) error {
+		b := ts.bucket(tx)
+		if b.Bucket(byTitleBucket).Get([]byte(title)) != nil {
+			return common.TopicTitleConflictError(fmt.Sprintf(
+				"topic %s already exists",
+				title,
+			))
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		id = int(seq)
+		return putTopic(b, nil, entity.NewTopic(id, title, ts.clock.Now(), s))
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (ts *BoltTopicRepository) RemoveTopic(id int) error {
+	return ts.db.Update(func(tx *bolt.Tx) error {
+		b := ts.bucket(tx)
+		topic, err := getTopic(b, id)
+		if err != nil {
+			if _, ok := err.(common.TopicNotExistsError); ok {
+				return nil
+			}
+			return err
+		}
+		if err = b.Bucket(byTitleBucket).Delete([]byte(topic.Title)); err != nil {
+			return err
+		}
+		if err = b.Bucket(byDueBucket).Delete(dueKey(topic.NextRepeat, id)); err != nil {
+			return err
+		}
+		return b.Bucket(byIdBucket).Delete(itob(uint64(id)))
+	})
+}
+
+func (ts *BoltTopicRepository) GetAllTopics() ([]*entity.Topic, error) {
+	res := make([]*entity.Topic, 0)
+	err := ts.db.View(func(tx *bolt.Tx) error {
+		return ts.bucket(tx).Bucket(byIdBucket).ForEach(func(_, v []byte) error {
+			t := new(entity.Topic)
+			if err := json.Unmarshal(v, t); err != nil {
+				return err
+			}
+			res = append(res, t)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (ts *BoltTopicRepository) GetDueTopics(now time.Time, limit int) ([]*entity.Topic, error) {
+	res := make([]*entity.Topic, 0)
+	err := ts.db.View(func(tx *bolt.Tx) error {
+		b := ts.bucket(tx)
+		c := b.Bucket(byDueBucket).Cursor()
+		end := dueTime(now)
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if limit > 0 && len(res) == limit {
+				break
+			}
+			// The keys have microseconds, so topics in the
+			// same microsecond as now are checked one by one.
+			if btoi(k[:8]) > end {
+				break
+			}
+			t, err := getTopic(b, int(btoi(k[8:])))
+			if err != nil {
+				return err
+			}
+			if t.NextRepeat.After(now) || !t.Active(now) {
+				continue
+			}
+			res = append(res, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (ts *BoltTopicRepository) GetTopicById(id int) (*entity.Topic, error) {
+	var topic *entity.Topic
+	err := ts.db.View(func(tx *bolt.Tx) error {
+		var err error
+		topic, err = getTopic(ts.bucket(tx), id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return topic, nil
+}
+
+func (ts *BoltTopicRepository) UpdateTopic(t *entity.Topic) error {
+	if t.Title == "" {
+		return common.TopicTitleError("topic's title is empty")
+	}
+	return ts.modifyTopic(t.Id, func(topic *entity.Topic) error {
+		*topic = *t
+		return nil
+	})
+}
+
+func (ts *BoltTopicRepository) SuspendTopic(id int) error {
+	return ts.modifyTopic(id, func(topic *entity.Topic) error {
+		topic.Suspended = true
+		return nil
+	})
+}
+
+func (ts *BoltTopicRepository) BuryTopic(id int, until time.Time) error {
+	return ts.modifyTopic(id, func(topic *entity.Topic) error {
+		topic.BuriedUntil = until
+		return nil
+	})
+}
+
+func (ts *BoltTopicRepository) UnsuspendTopic(id int) error {
+	return ts.modifyTopic(id, func(topic *entity.Topic) error {
+		topic.Suspended = false
+		topic.BuriedUntil = time.Time{}
+		return nil
+	})
+}
+
+// modifyTopic changes the topic with the given id
+// with modify and stores it in one transaction.
+func (ts *BoltTopicRepository) modifyTopic(id int, modify func(*entity.Topic) error) error {
+	return ts.db.Update(func(tx *bolt.Tx) error {
+		b := ts.bucket(tx)
+		old, err := getTopic(b, id)
+		if err != nil {
+			return err
+		}
+		topic := *old
+		if err = modify(&topic); err != nil {
+			return err
+		}
+		if topic.Title != old.Title && b.Bucket(byTitleBucket).Get([]byte(topic.Title)) != nil {
+			return common.TopicTitleConflictError(fmt.Sprintf(
+				"topic %s already exists",
+				topic.Title,
+			))
+		}
+		return putTopic(b, old, &topic)
+	})
+}
+
+// bucket returns the bucket of the user's topics,
+// which is created with the repository.
+func (ts *BoltTopicRepository) bucket(tx *bolt.Tx) *bolt.Bucket {
+	return tx.Bucket(topicsBucket).Bucket(ts.name)
+}
+
+func getTopic(b *bolt.Bucket, id int) (*entity.Topic, error) {
+	data := b.Bucket(byIdBucket).Get(itob(uint64(id)))
+	if data == nil {
+		return nil, common.TopicNotExistsError(
+			fmt.Sprintf("topic with id %d does not exist", id))
+	}
+	t := new(entity.Topic)
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// putTopic stores the topic and updates the indexes.
+// The old topic is the one being replaced or nil.
+func putTopic(b *bolt.Bucket, old, t *entity.Topic) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	titles := b.Bucket(byTitleBucket)
+	due := b.Bucket(byDueBucket)
+	if old != nil {
+		if err = titles.Delete([]byte(old.Title)); err != nil {
+			return err
+		}
+		if err = due.Delete(dueKey(old.NextRepeat, old.Id)); err != nil {
+			return err
+		}
+	}
+	id := itob(uint64(t.Id))
+	if err = titles.Put([]byte(t.Title), id); err != nil {
+		return err
+	}
+	if err = due.Put(dueKey(t.NextRepeat, t.Id), nil); err != nil {
+		return err
+	}
+	return b.Bucket(byIdBucket).Put(id, data)
+}
@@ -0,0 +1,22 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDueKeyOrder(t *testing.T) {
+	times := []time.Time{
+		{},
+		time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Unix(0, 0),
+		time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 12, 0, 0, 1000, time.UTC),
+	}
+	for i := 1; i < len(times); i++ {
+		a, b := dueKey(times[i-1], 2), dueKey(times[i], 1)
+		if string(a) >= string(b) {
+			t.Errorf("got key of %s not before key of %s", times[i-1], times[i])
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package boltdb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// storedUser is entity.User as it is stored. The password hash
+// is arbitrary bytes, which JSON strings cannot hold.
+type storedUser struct {
+	PasswdHash []byte `json:"passwdHash"`
+}
+
+// BoltUserRepository is a bbolt implementation of users repository.
+// It is safe for concurent use by multiple goroutines.
+type BoltUserRepository struct {
+	db *bolt.DB
+}
+
+func NewBoltUserRepository(db *bolt.DB) *BoltUserRepository {
+	return &BoltUserRepository{db: db}
+}
+
+func (r *BoltUserRepository) AddUser(u *entity.User) error {
+	if u.Name == "" {
+		return common.EmptyUserName("user name is empty")
+	}
+	data, err := json.Marshal(storedUser{PasswdHash: []byte(u.PasswdHash)})
+	if err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(usersBucket)
+		if b.Get([]byte(u.Name)) != nil {
+			return common.UserAlreadyExistsError(
+				fmt.Sprintf("user with name %s already exists",
+					u.Name),
+			)
+		}
+		return b.Put([]byte(u.Name), data)
+	})
+}
+
+func (r *BoltUserRepository) GetUser(name string) (*entity.User, error) {
+	var stored storedUser
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(usersBucket).Get([]byte(name))
+		if data == nil {
+			return common.UserNotExistError(
+				fmt.Sprintf(
+					"user with name %s does not exist", name,
+				),
+			)
+		}
+		return json.Unmarshal(data, &stored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entity.User{Name: name, PasswdHash: string(stored.PasswdHash)}, nil
+}
+
+func (r *BoltUserRepository) RemoveUser(name string) error {
+	if name == "" {
+		// bbolt does not accept empty keys.
+		return nil
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(usersBucket).Delete([]byte(name))
+	})
+}
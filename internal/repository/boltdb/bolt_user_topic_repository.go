@@ -0,0 +1,47 @@
+package boltdb
+
+import (
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltUserTopicRepository is a bbolt implementation of user
+// topics repository. Topics of every user are kept in their
+// own bucket. It is safe for concurent use by multiple goroutines.
+type BoltUserTopicRepository struct {
+	db    *bolt.DB
+	clock clock.Clock
+}
+
+func NewBoltUserTopicRepository(db *bolt.DB, clk clock.Clock) *BoltUserTopicRepository {
+	return &BoltUserTopicRepository{db: db, clock: clk}
+}
+
+func (r *BoltUserTopicRepository) GetUserTopicRepository(name string) (repo.TopicRepository, error) {
+	// The buckets exist after the first request of the user,
+	// so most requests need no write transaction.
+	var exists bool
+	err := r.db.View(func(tx *bolt.Tx) error {
+		exists = tx.Bucket(topicsBucket).Bucket([]byte(name)) != nil
+		return nil
+	})
+	if err == nil && !exists {
+		err = r.db.Update(func(tx *bolt.Tx) error {
+			b, err := tx.Bucket(topicsBucket).CreateBucketIfNotExists([]byte(name))
+			if err != nil {
+				return err
+			}
+			for _, index := range [][]byte{byIdBucket, byTitleBucket, byDueBucket} {
+				if _, err = b.CreateBucketIfNotExists(index); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &BoltTopicRepository{db: r.db, name: []byte(name), clock: r.clock}, nil
+}
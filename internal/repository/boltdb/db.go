@@ -0,0 +1,83 @@
+// Package boltdb keeps the repositories in an embedded bbolt file.
+// Every change is made in a transaction, so the file is consistent
+// after a crash.
+package boltdb
+
+import (
+	"encoding/binary"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Top-level buckets. Topics and reviews are kept in
+// a nested bucket per user named after the user.
+var (
+	usersBucket    = []byte("users")
+	topicsBucket   = []byte("topics")
+	settingsBucket = []byte("settings")
+	reviewsBucket  = []byte("reviews")
+)
+
+// Buckets nested in the topic bucket of a user. The bucket
+// itself gives topic ids from its sequence.
+var (
+	// byIdBucket maps topic ids to topics.
+	byIdBucket = []byte("byId")
+	// byTitleBucket maps titles to topic ids.
+	byTitleBucket = []byte("byTitle")
+	// byDueBucket has dueKey of every topic.
+	byDueBucket = []byte("byDue")
+)
+
+// Open opens the database at the given path, creating it
+// if needed, together with the top-level buckets.
+func Open(path string) (*bolt.DB, error) {
+	// The file is locked while it is open. The timeout makes
+	// a second server fail instead of waiting forever.
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{usersBucket, topicsBucket, settingsBucket, reviewsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// itob encodes an id or a sequence number as a key,
+// so the keys are sorted by the numbers.
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+func btoi(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
+
+// dueKey is the key of a topic in the due index. The keys are
+// sorted by the next repetition time in microseconds and then
+// by id. The sign bit of the time is flipped, so times before
+// the Unix epoch sort before those after it.
+func dueKey(nextRepeat time.Time, id int) []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, dueTime(nextRepeat))
+	binary.BigEndian.PutUint64(b[8:], uint64(id))
+	return b
+}
+
+func dueTime(t time.Time) uint64 {
+	return uint64(t.UnixMicro()) ^ 1<<63
+}
@@ -0,0 +1,139 @@
+package inmem
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// dueIndex is a min-heap of topics ordered by the time of their
+// next repetition. It remembers the position of every topic,
+// so a changed topic is moved to its new place in O(log n).
+// It is not safe for concurrent use.
+type dueIndex struct {
+	topics []*entity.Topic
+	pos    map[int]int
+}
+
+func newDueIndex() *dueIndex {
+	return &dueIndex{pos: make(map[int]int)}
+}
+
+func (d *dueIndex) Len() int { return len(d.topics) }
+
+func (d *dueIndex) Less(i, j int) bool {
+	return dueBefore(d.topics[i], d.topics[j])
+}
+
+func (d *dueIndex) Swap(i, j int) {
+	d.topics[i], d.topics[j] = d.topics[j], d.topics[i]
+	d.pos[d.topics[i].Id] = i
+	d.pos[d.topics[j].Id] = j
+}
+
+func (d *dueIndex) Push(x any) {
+	t := x.(*entity.Topic)
+	d.pos[t.Id] = len(d.topics)
+	d.topics = append(d.topics, t)
+}
+
+func (d *dueIndex) Pop() any {
+	n := len(d.topics) - 1
+	t := d.topics[n]
+	d.topics[n] = nil
+	d.topics = d.topics[:n]
+	delete(d.pos, t.Id)
+	return t
+}
+
+// add puts the topic into the index.
+func (d *dueIndex) add(t *entity.Topic) {
+	heap.Push(d, t)
+}
+
+// remove deletes the topic with the given id from the index.
+func (d *dueIndex) remove(id int) {
+	if i, ok := d.pos[id]; ok {
+		heap.Remove(d, i)
+	}
+}
+
+// update replaces the topic with the same id and restores
+// the order of the index.
+func (d *dueIndex) update(t *entity.Topic) {
+	i, ok := d.pos[t.Id]
+	if !ok {
+		d.add(t)
+		return
+	}
+	d.topics[i] = t
+	heap.Fix(d, i)
+}
+
+// due returns up to limit topics which are due and active at now
+// in the order of their next repetition. Non-positive limit means
+// no limit. Only the part of the heap holding due topics is visited.
+func (d *dueIndex) due(now time.Time, limit int) []*entity.Topic {
+	var res []*entity.Topic
+	if len(d.topics) == 0 {
+		return res
+	}
+
+	// The smallest topic not returned yet is always one
+	// of the candidates, so they are visited in order.
+	c := &dueCandidates{index: d}
+	heap.Push(c, 0)
+	for c.Len() > 0 && (limit <= 0 || len(res) < limit) {
+		i := heap.Pop(c).(int)
+		t := d.topics[i]
+		if t.NextRepeat.After(now) {
+			break
+		}
+		if t.Active(now) {
+			res = append(res, t)
+		}
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(d.topics) {
+				heap.Push(c, child)
+			}
+		}
+	}
+	return res
+}
+
+// dueCandidates is a min-heap of positions in a dueIndex.
+type dueCandidates struct {
+	index *dueIndex
+	pos   []int
+}
+
+func (c *dueCandidates) Len() int { return len(c.pos) }
+
+func (c *dueCandidates) Less(i, j int) bool {
+	return c.index.Less(c.pos[i], c.pos[j])
+}
+
+func (c *dueCandidates) Swap(i, j int) {
+	c.pos[i], c.pos[j] = c.pos[j], c.pos[i]
+}
+
+func (c *dueCandidates) Push(x any) {
+	c.pos = append(c.pos, x.(int))
+}
+
+func (c *dueCandidates) Pop() any {
+	n := len(c.pos) - 1
+	i := c.pos[n]
+	c.pos = c.pos[:n]
+	return i
+}
+
+// dueBefore reports whether a has to be repeated before b.
+// Topics due at the same time are ordered by id.
+func dueBefore(a, b *entity.Topic) bool {
+	if !a.NextRepeat.Equal(b.NextRepeat) {
+		return a.NextRepeat.Before(b.NextRepeat)
+	}
+	return a.Id < b.Id
+}
@@ -0,0 +1,33 @@
+package inmem
+
+import (
+	"testing"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/repotest"
+)
+
+func TestInmemTopicRepositoryContract(t *testing.T) {
+	repotest.TestTopicRepository(t, func(t *testing.T, clk clock.Clock) repo.TopicRepository {
+		return NewInmemTopicRepository(clk)
+	})
+}
+
+func TestInmemUserRepositoryContract(t *testing.T) {
+	repotest.TestUserRepository(t, func(t *testing.T) repo.UserRepository {
+		return NewInmemUserRepository()
+	})
+}
+
+func TestInmemSettingsRepositoryContract(t *testing.T) {
+	repotest.TestSettingsRepository(t, func(t *testing.T) repo.SettingsRepository {
+		return NewInmemSettingsRepository()
+	})
+}
+
+func TestInmemReviewRepositoryContract(t *testing.T) {
+	repotest.TestReviewRepository(t, func(t *testing.T) repo.ReviewRepository {
+		return NewInmemReviewRepository()
+	})
+}
@@ -0,0 +1,77 @@
+package inmem
+
+import (
+	"sync"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// InmemReviewRepository is an in-memory implementation
+// of review repository. It is safe for concurent use
+// by multiple goroutines.
+type InmemReviewRepository struct {
+	m       sync.Mutex
+	reviews map[string][]entity.ReviewEvent
+}
+
+func NewInmemReviewRepository() *InmemReviewRepository {
+	return &InmemReviewRepository{
+		reviews: make(map[string][]entity.ReviewEvent),
+	}
+}
+
+func (r *InmemReviewRepository) AddReview(name string, e *entity.ReviewEvent) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.reviews[name] = append(r.reviews[name], *e)
+	return nil
+}
+
+func (r *InmemReviewRepository) GetReviews(name string) ([]*entity.ReviewEvent, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	res := make([]*entity.ReviewEvent, 0, len(r.reviews[name]))
+	for _, e := range r.reviews[name] {
+		res = append(res, &e)
+	}
+	return res, nil
+}
+
+func (r *InmemReviewRepository) GetTopicReviews(name string, topicId int) ([]*entity.ReviewEvent, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	res := make([]*entity.ReviewEvent, 0)
+	for _, e := range r.reviews[name] {
+		if e.TopicId == topicId {
+			res = append(res, &e)
+		}
+	}
+	return res, nil
+}
+
+func (r *InmemReviewRepository) RemoveTopicReviews(name string, topicId int) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	kept := r.reviews[name][:0]
+	for _, e := range r.reviews[name] {
+		if e.TopicId != topicId {
+			kept = append(kept, e)
+		}
+	}
+	r.reviews[name] = kept
+	return nil
+}
+
+// GetAllReviews returns reviews of all users
+// in chronological order.
+func (r *InmemReviewRepository) GetAllReviews() map[string][]*entity.ReviewEvent {
+	r.m.Lock()
+	defer r.m.Unlock()
+	res := make(map[string][]*entity.ReviewEvent, len(r.reviews))
+	for name, events := range r.reviews {
+		for _, e := range events {
+			res[name] = append(res[name], &e)
+		}
+	}
+	return res
+}
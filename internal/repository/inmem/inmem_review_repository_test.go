@@ -0,0 +1,63 @@
+package inmem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestAddReviewAndGetReviews(t *testing.T) {
+	repo := NewInmemReviewRepository()
+	now := time.Now()
+
+	events := []*entity.ReviewEvent{
+		{TopicId: 1, Time: now, Grade: entity.GradeGood},
+		{TopicId: 2, Time: now.Add(time.Minute), Grade: entity.GradeHard},
+		{TopicId: 1, Time: now.Add(time.Hour), Grade: entity.GradeWrong},
+	}
+	for _, e := range events {
+		if err := repo.AddReview("User", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo.AddReview("OtherUser", &entity.ReviewEvent{TopicId: 1})
+
+	all, err := repo.GetReviews("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("got len(all) = %d; want 3", len(all))
+	}
+
+	reviews, err := repo.GetTopicReviews("User", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("got len(reviews) = %d; want 2", len(reviews))
+	}
+	if reviews[0].Grade != entity.GradeGood || reviews[1].Grade != entity.GradeWrong {
+		t.Errorf("got grades %d, %d; want %d, %d", reviews[0].Grade,
+			reviews[1].Grade, entity.GradeGood, entity.GradeWrong)
+	}
+}
+
+func TestRemoveTopicReviews(t *testing.T) {
+	repo := NewInmemReviewRepository()
+
+	repo.AddReview("User", &entity.ReviewEvent{TopicId: 1})
+	repo.AddReview("User", &entity.ReviewEvent{TopicId: 2})
+	repo.AddReview("OtherUser", &entity.ReviewEvent{TopicId: 1})
+
+	if err := repo.RemoveTopicReviews("User", 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.reviews["User"]) != 1 {
+		t.Errorf("got len(repo.reviews[\"User\"]) = %d; want 1", len(repo.reviews["User"]))
+	}
+	if len(repo.reviews["OtherUser"]) != 1 {
+		t.Errorf("got len(repo.reviews[\"OtherUser\"]) = %d; want 1", len(repo.reviews["OtherUser"]))
+	}
+}
@@ -0,0 +1,49 @@
+package inmem
+
+import (
+	"sync"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// InmemSettingsRepository is an in-memory implementation
+// of settings repository. It is safe for concurent use
+// by multiple goroutines.
+type InmemSettingsRepository struct {
+	m        sync.Mutex
+	settings map[string]*entity.Settings
+}
+
+func NewInmemSettingsRepository() *InmemSettingsRepository {
+	return &InmemSettingsRepository{
+		settings: make(map[string]*entity.Settings),
+	}
+}
+
+func (r *InmemSettingsRepository) GetSettings(name string) (*entity.Settings, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	s, ok := r.settings[name]
+	if !ok {
+		return &entity.Settings{}, nil
+	}
+	return s.Clone(), nil
+}
+
+func (r *InmemSettingsRepository) SetSettings(name string, s *entity.Settings) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.settings[name] = s.Clone()
+	return nil
+}
+
+// GetAllSettings returns settings of all users who saved them.
+func (r *InmemSettingsRepository) GetAllSettings() map[string]*entity.Settings {
+	r.m.Lock()
+	defer r.m.Unlock()
+	res := make(map[string]*entity.Settings, len(r.settings))
+	for name, s := range r.settings {
+		res[name] = s.Clone()
+	}
+	return res
+}
@@ -3,7 +3,9 @@ package inmem
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
 	"github.com/Ayaya-zx/mem-flow/internal/common"
 	"github.com/Ayaya-zx/mem-flow/internal/entity"
 )
@@ -14,38 +16,64 @@ type InmemTopicRepository struct {
 	m           sync.Mutex
 	topics      map[int]*entity.Topic
 	topicTitles map[string]struct{}
+	due         *dueIndex
 	nextId      int
+	clock       clock.Clock
 }
 
-func NewInmemTopicRepository() *InmemTopicRepository {
+func NewInmemTopicRepository(clk clock.Clock) *InmemTopicRepository {
 	return &InmemTopicRepository{
 		topics:      make(map[int]*entity.Topic),
 		topicTitles: make(map[string]struct{}),
+		due:         newDueIndex(),
 		nextId:      1,
+		clock:       clk,
 	}
 }
 
-func (ts *InmemTopicRepository) AddTopic(title string) (int, error) {
-	if title == "" {
-		return 0, common.TopicTitleError("topic's title is empty")
+func (ts *InmemTopicRepository) AddTopic(title string, s entity.Scheduler) (int, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+
+	topic, err := ts.newTopic(title, s)
+	if err != nil {
+		return 0, err
 	}
+	ts.nextId++
+	ts.topics[topic.Id] = topic
+	ts.topicTitles[title] = struct{}{}
+	ts.due.add(topic)
+
+	return topic.Id, nil
+}
 
+// NewTopic returns the topic AddTopic would add without adding it,
+// so the caller can add it later with PutTopic.
+func (ts *InmemTopicRepository) NewTopic(title string, s entity.Scheduler) (*entity.Topic, error) {
 	ts.m.Lock()
 	defer ts.m.Unlock()
+	return ts.newTopic(title, s)
+}
 
+func (ts *InmemTopicRepository) newTopic(title string, s entity.Scheduler) (*entity.Topic, error) {
+	if err := ts.checkTitle(title); err != nil {
+		return nil, err
+	}
+	return entity.NewTopic(ts.nextId, title, ts.clock.Now(), s), nil
+}
+
+// checkTitle returns an error if the title is empty or taken.
+func (ts *InmemTopicRepository) checkTitle(title string) error {
+	if title == "" {
+		return common.TopicTitleError("topic's title is empty")
+	}
 	if _, ok := ts.topicTitles[title]; ok {
-		return 0, common.TopicTitleError(fmt.Sprintf(
+		return common.TopicTitleConflictError(fmt.Sprintf(
 			"topic %s already exists",
 			title,
 		))
 	}
-
-	topic := entity.NewTopic(ts.nextId, title)
-	ts.nextId++
-	ts.topics[topic.Id] = topic
-	ts.topicTitles[title] = struct{}{}
-
-	return topic.Id, nil
+	return nil
 }
 
 func (ts *InmemTopicRepository) RemoveTopic(id int) error {
@@ -54,6 +82,7 @@ func (ts *InmemTopicRepository) RemoveTopic(id int) error {
 	if topic, ok := ts.topics[id]; ok {
 		delete(ts.topics, id)
 		delete(ts.topicTitles, topic.Title)
+		ts.due.remove(id)
 	}
 	return nil
 }
@@ -63,7 +92,20 @@ func (ts *InmemTopicRepository) GetAllTopics() ([]*entity.Topic, error) {
 	defer ts.m.Unlock()
 	res := make([]*entity.Topic, 0, len(ts.topics))
 	for _, t := range ts.topics {
-		res = append(res, t)
+		topic := *t
+		res = append(res, &topic)
+	}
+	return res, nil
+}
+
+func (ts *InmemTopicRepository) GetDueTopics(now time.Time, limit int) ([]*entity.Topic, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	due := ts.due.due(now, limit)
+	res := make([]*entity.Topic, 0, len(due))
+	for _, t := range due {
+		topic := *t
+		res = append(res, &topic)
 	}
 	return res, nil
 }
@@ -76,5 +118,106 @@ func (ts *InmemTopicRepository) GetTopicById(id int) (*entity.Topic, error) {
 		return nil, common.TopicNotExistsError(
 			fmt.Sprintf("topic with id %d does not exist", id))
 	}
-	return t, nil
+	topic := *t
+	return &topic, nil
+}
+
+func (ts *InmemTopicRepository) UpdateTopic(t *entity.Topic) error {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	if err := ts.checkUpdate(t); err != nil {
+		return err
+	}
+	if old := ts.topics[t.Id]; t.Title != old.Title {
+		delete(ts.topicTitles, old.Title)
+		ts.topicTitles[t.Title] = struct{}{}
+	}
+	topic := *t
+	ts.topics[t.Id] = &topic
+	ts.due.update(&topic)
+	return nil
+}
+
+// CheckUpdate returns the error UpdateTopic would return
+// for the topic without updating it.
+func (ts *InmemTopicRepository) CheckUpdate(t *entity.Topic) error {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	return ts.checkUpdate(t)
+}
+
+func (ts *InmemTopicRepository) checkUpdate(t *entity.Topic) error {
+	old, ok := ts.topics[t.Id]
+	if !ok {
+		return common.TopicNotExistsError(
+			fmt.Sprintf("topic with id %d does not exist", t.Id))
+	}
+	if t.Title != old.Title {
+		return ts.checkTitle(t.Title)
+	}
+	return nil
+}
+
+func (ts *InmemTopicRepository) SuspendTopic(id int) error {
+	return ts.modifyTopic(id, func(t *entity.Topic) {
+		t.Suspended = true
+	})
+}
+
+func (ts *InmemTopicRepository) BuryTopic(id int, until time.Time) error {
+	return ts.modifyTopic(id, func(t *entity.Topic) {
+		t.BuriedUntil = until
+	})
+}
+
+func (ts *InmemTopicRepository) UnsuspendTopic(id int) error {
+	return ts.modifyTopic(id, func(t *entity.Topic) {
+		t.Suspended = false
+		t.BuriedUntil = time.Time{}
+	})
+}
+
+func (ts *InmemTopicRepository) modifyTopic(id int, modify func(t *entity.Topic)) error {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	t, ok := ts.topics[id]
+	if !ok {
+		return common.TopicNotExistsError(
+			fmt.Sprintf("topic with id %d does not exist", id))
+	}
+	modify(t)
+	ts.due.update(t)
+	return nil
+}
+
+// NextId returns the id the next added topic will get.
+func (ts *InmemTopicRepository) NextId() int {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	return ts.nextId
+}
+
+// SetNextId makes the next added topic get the given id
+// unless there are topics with greater ids.
+func (ts *InmemTopicRepository) SetNextId(id int) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	ts.nextId = max(ts.nextId, id)
+}
+
+// PutTopic stores a copy of the topic as it is, replacing the one
+// with the same id. Unlike AddTopic and UpdateTopic it does not
+// check the title, so it is only used to restore saved topics or
+// to store the ones checked with NewTopic or CheckUpdate.
+func (ts *InmemTopicRepository) PutTopic(t *entity.Topic) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	if old, ok := ts.topics[t.Id]; ok {
+		delete(ts.topicTitles, old.Title)
+	}
+	topic := *t
+	ts.topics[t.Id] = &topic
+	ts.topicTitles[t.Title] = struct{}{}
+	ts.due.update(&topic)
+	ts.nextId = max(ts.nextId, t.Id+1)
 }
@@ -1,13 +1,18 @@
 package inmem
 
-import repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+import (
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+)
 
-type InmemTopicRepositoryFactory struct{}
+type InmemTopicRepositoryFactory struct {
+	clock clock.Clock
+}
 
-func NewInmemTopicRepositoryFactory() *InmemTopicRepositoryFactory {
-	return &InmemTopicRepositoryFactory{}
+func NewInmemTopicRepositoryFactory(clk clock.Clock) *InmemTopicRepositoryFactory {
+	return &InmemTopicRepositoryFactory{clock: clk}
 }
 
-func (InmemTopicRepositoryFactory) CreateTopicRepository() (repo.TopicRepository, error) {
-	return NewInmemTopicRepository(), nil
+func (f InmemTopicRepositoryFactory) CreateTopicRepository() (repo.TopicRepository, error) {
+	return NewInmemTopicRepository(f.clock), nil
 }
@@ -1,15 +1,23 @@
 package inmem
 
 import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
 	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	"github.com/Ayaya-zx/mem-flow/internal/scheduler"
 )
 
 func TestAddTopicAndGetTopic(t *testing.T) {
-	repo := NewInmemTopicRepository()
+	repo := NewInmemTopicRepository(clock.Real{})
 	nextId := repo.nextId
 
 	// Add task
-	id, err := repo.AddTopic("MyTopic")
+	id, err := repo.AddTopic("MyTopic", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -37,11 +45,11 @@ func TestAddTopicAndGetTopic(t *testing.T) {
 }
 
 func TestAddTopicWithEmptyTitle(t *testing.T) {
-	repo := NewInmemTopicRepository()
+	repo := NewInmemTopicRepository(clock.Real{})
 	id := repo.nextId
 
 	// Adding a task with an empty title is prohibited
-	_, err := repo.AddTopic("")
+	_, err := repo.AddTopic("", nil)
 	if err == nil {
 		t.Errorf("got nil; want error")
 	}
@@ -54,13 +62,13 @@ func TestAddTopicWithEmptyTitle(t *testing.T) {
 }
 
 func TestAddTopicWithSameTitleTwice(t *testing.T) {
-	repo := NewInmemTopicRepository()
+	repo := NewInmemTopicRepository(clock.Real{})
 
-	repo.AddTopic("MyTopic")
+	repo.AddTopic("MyTopic", nil)
 	nextId := repo.nextId
 
 	// We should not be able to add a task with same name twice
-	_, err := repo.AddTopic("MyTopic")
+	_, err := repo.AddTopic("MyTopic", nil)
 	if err == nil {
 		t.Errorf("got nil; want error")
 	}
@@ -70,9 +78,9 @@ func TestAddTopicWithSameTitleTwice(t *testing.T) {
 }
 
 func TestRemoveTopic(t *testing.T) {
-	repo := NewInmemTopicRepository()
+	repo := NewInmemTopicRepository(clock.Real{})
 
-	id, err := repo.AddTopic("MyTopic")
+	id, err := repo.AddTopic("MyTopic", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -98,9 +106,9 @@ func TestGetTopic(t *testing.T) {
 		{"MyTopic3", 3},
 	}
 
-	topicRepo := NewInmemTopicRepository()
+	topicRepo := NewInmemTopicRepository(clock.Real{})
 	for _, test := range tests {
-		topicRepo.AddTopic(test.title)
+		topicRepo.AddTopic(test.title, nil)
 	}
 
 	for _, test := range tests {
@@ -120,3 +128,179 @@ func TestGetTopic(t *testing.T) {
 		t.Errorf("got nil; want err")
 	}
 }
+
+func TestUpdateTopic(t *testing.T) {
+	repo := NewInmemTopicRepository(clock.Real{})
+
+	id, err := repo.AddTopic("MyTopic", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.AddTopic("OtherTopic", nil)
+
+	topic, err := repo.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic.State.Repetitions = 3
+	topic.Title = "RenamedTopic"
+	if err = repo.UpdateTopic(topic); err != nil {
+		t.Fatal(err)
+	}
+
+	topic, err = repo.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topic.State.Repetitions != 3 {
+		t.Errorf("got topic.State.Repetitions = %d; want 3", topic.State.Repetitions)
+	}
+	if _, ok := repo.topicTitles["MyTopic"]; ok {
+		t.Errorf("got repo.topicTitles[\"MyTopic\"] = true; want false")
+	}
+
+	// Renaming to an existing title is prohibited
+	topic.Title = "OtherTopic"
+	if err = repo.UpdateTopic(topic); err == nil {
+		t.Errorf("got nil; want error")
+	}
+
+	topic.Id = 100
+	if err = repo.UpdateTopic(topic); err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
+
+func TestAddTopicSchedulesFirstRepetition(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewInmemTopicRepository(clock.NewFake(now))
+	sched, err := scheduler.New(scheduler.DefaultName, &entity.Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := repo.AddTopic("MyTopic", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, err := repo.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !topic.Created.Equal(now) {
+		t.Errorf("got topic.Created = %s; want %s", topic.Created, now)
+	}
+	if got := topic.NextRepeat.Sub(now); got != 20*time.Minute {
+		t.Errorf("got first interval %s; want 20m", got)
+	}
+}
+
+func TestSuspendAndBuryTopic(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewInmemTopicRepository(clock.NewFake(now))
+
+	id, err := repo.AddTopic("MyTopic", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repo.SuspendTopic(id); err != nil {
+		t.Fatal(err)
+	}
+	topic, _ := repo.GetTopicById(id)
+	if topic.Active(now) {
+		t.Errorf("got suspended topic active; want inactive")
+	}
+
+	if err = repo.UnsuspendTopic(id); err != nil {
+		t.Fatal(err)
+	}
+	topic, _ = repo.GetTopicById(id)
+	if !topic.Active(now) {
+		t.Errorf("got unsuspended topic inactive; want active")
+	}
+
+	tomorrow := now.Add(12 * time.Hour)
+	if err = repo.BuryTopic(id, tomorrow); err != nil {
+		t.Fatal(err)
+	}
+	topic, _ = repo.GetTopicById(id)
+	if topic.Active(now) {
+		t.Errorf("got buried topic active; want inactive")
+	}
+	if !topic.Active(tomorrow) {
+		t.Errorf("got buried topic inactive after burial; want active")
+	}
+
+	if err = repo.SuspendTopic(100); err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
+
+func TestGetDueTopics(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewInmemTopicRepository(clock.NewFake(now))
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	for i := 0; i < 200; i++ {
+		id, err := repo.AddTopic(fmt.Sprintf("Topic%d", i), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		topic, _ := repo.GetTopicById(id)
+		topic.NextRepeat = now.Add(time.Duration(rnd.IntN(48)-24) * time.Hour)
+		if err = repo.UpdateTopic(topic); err != nil {
+			t.Fatal(err)
+		}
+		switch rnd.IntN(10) {
+		case 0:
+			repo.SuspendTopic(id)
+		case 1:
+			repo.BuryTopic(id, now.Add(time.Hour))
+		case 2:
+			repo.RemoveTopic(id)
+		}
+	}
+
+	all, _ := repo.GetAllTopics()
+	var want []*entity.Topic
+	for _, topic := range all {
+		if topic.Active(now) && !topic.NextRepeat.After(now) {
+			want = append(want, topic)
+		}
+	}
+	slices.SortFunc(want, func(a, b *entity.Topic) int {
+		if c := a.NextRepeat.Compare(b.NextRepeat); c != 0 {
+			return c
+		}
+		return a.Id - b.Id
+	})
+
+	for _, limit := range []int{0, 1, 10, len(want), len(want) + 10} {
+		got, err := repo.GetDueTopics(now, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := len(want)
+		if limit > 0 {
+			n = min(n, limit)
+		}
+		if len(got) != n {
+			t.Errorf("limit %d: got %d topics; want %d", limit, len(got), n)
+			continue
+		}
+		for i := range got {
+			if got[i].Id != want[i].Id {
+				t.Errorf("limit %d: got topic %d at %d; want %d", limit, got[i].Id, i, want[i].Id)
+				break
+			}
+		}
+	}
+
+	// The returned topics are copies
+	got, _ := repo.GetDueTopics(now, 1)
+	got[0].NextRepeat = now.Add(time.Hour)
+	if again, _ := repo.GetDueTopics(now, 1); again[0].Id != got[0].Id {
+		t.Errorf("got topic %d after changing the copy; want %d", again[0].Id, got[0].Id)
+	}
+}
@@ -57,3 +57,15 @@ func (r *InmemUserRepository) RemoveUser(name string) (err error) {
 	delete(r.users, name)
 	return nil
 }
+
+// GetAllUsers returns all users stored at the repository.
+func (r *InmemUserRepository) GetAllUsers() []*entity.User {
+	r.m.Lock()
+	defer r.m.Unlock()
+	res := make([]*entity.User, 0, len(r.users))
+	for _, u := range r.users {
+		user := *u
+		res = append(res, &user)
+	}
+	return res
+}
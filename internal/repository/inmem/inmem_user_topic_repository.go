@@ -36,3 +36,15 @@ func (r *InmemUserTopicRepository) GetUserTopicRepository(name string) (repo.Top
 	}
 	return topicRepo, nil
 }
+
+// GetUserNames returns names of the users who have
+// topic repositories.
+func (r *InmemUserTopicRepository) GetUserNames() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	res := make([]string, 0, len(r.userTopicRepo))
+	for name := range r.userTopicRepo {
+		res = append(res, name)
+	}
+	return res
+}
@@ -0,0 +1,429 @@
+// Package journal makes the in-memory repositories durable. Every
+// change is appended to a log file which is synced to disk before
+// the change is reported done. On start the repositories are restored
+// from the last snapshot and the log written after it. Snapshots are
+// taken periodically, so the log does not grow without bound.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/inmem"
+)
+
+// DefaultSnapshotEvery is the number of log entries
+// after which a snapshot is taken by default.
+const DefaultSnapshotEvery = 1000
+
+const (
+	logName      = "journal.log"
+	snapshotName = "snapshot.json"
+)
+
+// Operations recorded in the log.
+const (
+	opAddUser            = "addUser"
+	opRemoveUser         = "removeUser"
+	opPutTopic           = "putTopic"
+	opRemoveTopic        = "removeTopic"
+	opSetSettings        = "setSettings"
+	opAddReview          = "addReview"
+	opRemoveTopicReviews = "removeTopicReviews"
+)
+
+// entry is a line of the log. Topics are recorded as they are
+// after the change, so replaying does not depend on the clock.
+type entry struct {
+	Seq      uint64              `json:"seq"`
+	Op       string              `json:"op"`
+	Name     string              `json:"name,omitempty"`
+	User     *user               `json:"user,omitempty"`
+	Topic    *entity.Topic       `json:"topic,omitempty"`
+	TopicId  int                 `json:"topicId,omitempty"`
+	Settings *entity.Settings    `json:"settings,omitempty"`
+	Review   *entity.ReviewEvent `json:"review,omitempty"`
+}
+
+// snapshot is the whole state of the repositories after
+// the log entry with the sequence number Seq.
+type snapshot struct {
+	Seq      uint64                           `json:"seq"`
+	Users    []*user                          `json:"users"`
+	Topics   map[string]topicsSnapshot        `json:"topics"`
+	Settings map[string]*entity.Settings      `json:"settings"`
+	Reviews  map[string][]*entity.ReviewEvent `json:"reviews"`
+}
+
+// user is entity.User as it is stored. The password hash
+// is arbitrary bytes, which JSON strings cannot hold.
+type user struct {
+	Name       string `json:"name"`
+	PasswdHash []byte `json:"passwdHash"`
+}
+
+func newUser(u *entity.User) *user {
+	return &user{Name: u.Name, PasswdHash: []byte(u.PasswdHash)}
+}
+
+func (u *user) entity() *entity.User {
+	return &entity.User{Name: u.Name, PasswdHash: string(u.PasswdHash)}
+}
+
+type topicsSnapshot struct {
+	NextId int             `json:"nextId"`
+	Topics []*entity.Topic `json:"topics"`
+}
+
+// Journal keeps the in-memory repositories and their log.
+// It is safe for concurent use by multiple goroutines.
+type Journal struct {
+	// m is held while a change is logged and applied,
+	// so the log has the changes in the order they happened.
+	m             sync.Mutex
+	dir           string
+	log           *os.File
+	seq           uint64
+	sinceSnapshot int
+	snapshotEvery int
+
+	users      *inmem.InmemUserRepository
+	userTopics *inmem.InmemUserTopicRepository
+	settings   *inmem.InmemSettingsRepository
+	reviews    *inmem.InmemReviewRepository
+}
+
+// Open restores the repositories from the directory, creating it
+// if needed. A snapshot is taken after every snapshotEvery changes,
+// zero means DefaultSnapshotEvery.
+func Open(dir string, snapshotEvery int, clk clock.Clock) (*Journal, error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = DefaultSnapshotEvery
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	j := &Journal{
+		dir:           dir,
+		snapshotEvery: snapshotEvery,
+		users:         inmem.NewInmemUserRepository(),
+		userTopics:    inmem.NewInmemUserTopicRepository(inmem.NewInmemTopicRepositoryFactory(clk)),
+		settings:      inmem.NewInmemSettingsRepository(),
+		reviews:       inmem.NewInmemReviewRepository(),
+	}
+
+	if err := j.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	replayed, err := j.replay()
+	if err != nil {
+		return nil, err
+	}
+
+	j.log, err = os.OpenFile(filepath.Join(dir, logName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if replayed > 0 {
+		// Start with a compact log.
+		if err = j.snapshot(); err != nil {
+			j.log.Close()
+			return nil, err
+		}
+	}
+	return j, nil
+}
+
+func (j *Journal) UserRepository() repo.UserRepository {
+	return &userRepository{j: j}
+}
+
+func (j *Journal) UserTopicRepository() repo.UserTopicRepository {
+	return &userTopicRepository{j: j}
+}
+
+func (j *Journal) SettingsRepository() repo.SettingsRepository {
+	return &settingsRepository{j: j}
+}
+
+func (j *Journal) ReviewRepository() repo.ReviewRepository {
+	return &reviewRepository{j: j}
+}
+
+// Close takes a snapshot and closes the log.
+func (j *Journal) Close() error {
+	j.m.Lock()
+	defer j.m.Unlock()
+	err := j.snapshot()
+	if closeErr := j.log.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// change logs the entry returned by prepare and then applies it.
+// prepare checks that the change can be made and describes it
+// without making it. If it returns a nil entry, nothing is done.
+// A change which cannot be logged is not made, so the repositories
+// never have what would be lost after a restart.
+func (j *Journal) change(prepare func() (*entry, error)) error {
+	j.m.Lock()
+	defer j.m.Unlock()
+
+	e, err := prepare()
+	if err != nil || e == nil {
+		return err
+	}
+
+	e.Seq = j.seq + 1
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	info, err := j.log.Stat()
+	if err != nil {
+		return err
+	}
+	_, err = j.log.Write(append(data, '\n'))
+	if err == nil {
+		err = j.log.Sync()
+	}
+	if err != nil {
+		// Cut off what might have been written, so the entry
+		// is not replayed and the following ones stay readable.
+		j.log.Truncate(info.Size())
+		return err
+	}
+	j.seq = e.Seq
+	if err = j.apply(e); err != nil {
+		return err
+	}
+
+	j.sinceSnapshot++
+	if j.sinceSnapshot >= j.snapshotEvery {
+		// The change is already logged, so a failed snapshot
+		// only leaves the log longer. It is tried again
+		// after the next change.
+		if err = j.snapshot(); err != nil {
+			log.Println("journal snapshot:", err)
+		}
+	}
+	return nil
+}
+
+// apply makes the logged change.
+func (j *Journal) apply(e *entry) error {
+	switch e.Op {
+	case opAddUser:
+		// The user might be added again if the log
+		// was not truncated after a snapshot.
+		j.users.RemoveUser(e.User.Name)
+		return j.users.AddUser(e.User.entity())
+	case opRemoveUser:
+		return j.users.RemoveUser(e.Name)
+	case opPutTopic:
+		topics, err := j.topicRepository(e.Name)
+		if err != nil {
+			return err
+		}
+		topics.PutTopic(e.Topic)
+		return nil
+	case opRemoveTopic:
+		topics, err := j.topicRepository(e.Name)
+		if err != nil {
+			return err
+		}
+		return topics.RemoveTopic(e.TopicId)
+	case opSetSettings:
+		return j.settings.SetSettings(e.Name, e.Settings)
+	case opAddReview:
+		return j.reviews.AddReview(e.Name, e.Review)
+	case opRemoveTopicReviews:
+		return j.reviews.RemoveTopicReviews(e.Name, e.TopicId)
+	}
+	return fmt.Errorf("unknown journal operation %q", e.Op)
+}
+
+func (j *Journal) topicRepository(name string) (*inmem.InmemTopicRepository, error) {
+	topics, err := j.userTopics.GetUserTopicRepository(name)
+	if err != nil {
+		return nil, err
+	}
+	return topics.(*inmem.InmemTopicRepository), nil
+}
+
+// replay applies the log entries written after the snapshot
+// and returns their number. A torn last line left by a crash
+// during a write is dropped.
+func (j *Journal) replay() (int, error) {
+	path := filepath.Join(j.dir, logName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if i := bytes.LastIndexByte(data, '\n'); i+1 < len(data) {
+		data = data[:i+1]
+		if err = os.Truncate(path, int64(len(data))); err != nil {
+			return 0, err
+		}
+	}
+
+	replayed := 0
+	r := bufio.NewReader(bytes.NewReader(data))
+	for line := 1; ; line++ {
+		raw, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return replayed, nil
+		}
+		e := new(entry)
+		if err = json.Unmarshal(raw, e); err != nil {
+			return 0, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if e.Seq <= j.seq {
+			// Already in the snapshot.
+			continue
+		}
+		if err = j.apply(e); err != nil {
+			return 0, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		j.seq = e.Seq
+		replayed++
+	}
+}
+
+func (j *Journal) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(j.dir, snapshotName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s := new(snapshot)
+	if err = json.Unmarshal(data, s); err != nil {
+		return fmt.Errorf("%s: %w", snapshotName, err)
+	}
+	j.seq = s.Seq
+	for _, u := range s.Users {
+		if err = j.users.AddUser(u.entity()); err != nil {
+			return err
+		}
+	}
+	for name, ts := range s.Topics {
+		topics, err := j.topicRepository(name)
+		if err != nil {
+			return err
+		}
+		for _, t := range ts.Topics {
+			topics.PutTopic(t)
+		}
+		topics.SetNextId(ts.NextId)
+	}
+	for name, settings := range s.Settings {
+		if err = j.settings.SetSettings(name, settings); err != nil {
+			return err
+		}
+	}
+	for name, events := range s.Reviews {
+		for _, e := range events {
+			if err = j.reviews.AddReview(name, e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// snapshot writes the whole state and empties the log.
+// It must be called with j.m held.
+func (j *Journal) snapshot() error {
+	s := &snapshot{
+		Seq:      j.seq,
+		Users:    make([]*user, 0),
+		Topics:   make(map[string]topicsSnapshot),
+		Settings: j.settings.GetAllSettings(),
+		Reviews:  j.reviews.GetAllReviews(),
+	}
+	for _, u := range j.users.GetAllUsers() {
+		s.Users = append(s.Users, newUser(u))
+	}
+	for _, name := range j.userTopics.GetUserNames() {
+		topics, err := j.topicRepository(name)
+		if err != nil {
+			return err
+		}
+		all, err := topics.GetAllTopics()
+		if err != nil {
+			return err
+		}
+		s.Topics[name] = topicsSnapshot{NextId: topics.NextId(), Topics: all}
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	// The new snapshot replaces the old one at once, so a crash
+	// leaves one of them whole. Entries of the log which are
+	// already in the snapshot are skipped by their numbers.
+	tmp := filepath.Join(j.dir, snapshotName+".tmp")
+	if err = writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, filepath.Join(j.dir, snapshotName)); err != nil {
+		return err
+	}
+	if err = syncDir(j.dir); err != nil {
+		return err
+	}
+
+	if err = j.log.Truncate(0); err != nil {
+		return err
+	}
+	if err = j.log.Sync(); err != nil {
+		return err
+	}
+	j.sinceSnapshot = 0
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
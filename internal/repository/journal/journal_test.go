@@ -0,0 +1,247 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/repotest"
+)
+
+// openTestJournal opens a journal in an empty directory
+// which is closed at the end of the test.
+func openTestJournal(t *testing.T, clk clock.Clock) *Journal {
+	j, err := Open(t.TempDir(), 0, clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { j.Close() })
+	return j
+}
+
+func TestJournalTopicRepositoryContract(t *testing.T) {
+	repotest.TestTopicRepository(t, func(t *testing.T, clk clock.Clock) repo.TopicRepository {
+		r, err := openTestJournal(t, clk).UserTopicRepository().GetUserTopicRepository("alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	})
+}
+
+func TestJournalUserTopicRepositoryContract(t *testing.T) {
+	dir := t.TempDir()
+	repotest.TestUserTopicRepository(t, func(t *testing.T, clk clock.Clock) (repo.UserTopicRepository, func()) {
+		j, err := Open(dir, 0, clk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return j.UserTopicRepository(), func() { j.Close() }
+	})
+}
+
+func TestJournalUserRepositoryContract(t *testing.T) {
+	repotest.TestUserRepository(t, func(t *testing.T) repo.UserRepository {
+		return openTestJournal(t, clock.Real{}).UserRepository()
+	})
+}
+
+func TestJournalSettingsRepositoryContract(t *testing.T) {
+	repotest.TestSettingsRepository(t, func(t *testing.T) repo.SettingsRepository {
+		return openTestJournal(t, clock.Real{}).SettingsRepository()
+	})
+}
+
+func TestJournalReviewRepositoryContract(t *testing.T) {
+	repotest.TestReviewRepository(t, func(t *testing.T) repo.ReviewRepository {
+		return openTestJournal(t, clock.Real{}).ReviewRepository()
+	})
+}
+
+// passwdHash is not valid UTF-8 like real hashes.
+const passwdHash = "\x00\xff\xfe hash"
+
+// fill makes a few changes of every kind and returns
+// the id of a topic which is left in the repository.
+func fill(t *testing.T, j *Journal) int {
+	t.Helper()
+	if err := j.UserRepository().AddUser(&entity.User{Name: "alice", PasswdHash: passwdHash}); err != nil {
+		t.Fatal(err)
+	}
+	topics, err := j.UserTopicRepository().GetUserTopicRepository("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := topics.AddTopic("Kept", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, err := topics.AddTopic("Removed", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = topics.RemoveTopic(removed); err != nil {
+		t.Fatal(err)
+	}
+	if err = topics.SuspendTopic(id); err != nil {
+		t.Fatal(err)
+	}
+	if err = j.SettingsRepository().SetSettings("alice", &entity.Settings{Scheduler: "leitner"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = j.ReviewRepository().AddReview("alice", &entity.ReviewEvent{TopicId: id, Grade: entity.GradeGood}); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// checkFilled checks the state left by fill.
+func checkFilled(t *testing.T, j *Journal, id int) {
+	t.Helper()
+	u, err := j.UserRepository().GetUser("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.PasswdHash != passwdHash {
+		t.Errorf("got password hash %q; want %q", u.PasswdHash, passwdHash)
+	}
+	topics, err := j.UserTopicRepository().GetUserTopicRepository("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := topics.GetAllTopics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("got %d topics; want 1", len(all))
+	}
+	if all[0].Id != id || all[0].Title != "Kept" || !all[0].Suspended {
+		t.Errorf("got topic %d %q suspended %t; want %d \"Kept\" suspended",
+			all[0].Id, all[0].Title, all[0].Suspended, id)
+	}
+	// Ids of removed topics are not reused
+	next, err := topics.AddTopic("New", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next <= id+1 {
+		t.Errorf("got id %d for a new topic; want more than %d", next, id+1)
+	}
+	settings, err := j.SettingsRepository().GetSettings("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Scheduler != "leitner" {
+		t.Errorf("got scheduler %q; want \"leitner\"", settings.Scheduler)
+	}
+	reviews, err := j.ReviewRepository().GetReviews("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews) != 1 {
+		t.Errorf("got %d reviews; want 1", len(reviews))
+	}
+}
+
+func TestReopen(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	for _, snapshotEvery := range []int{1, 3, DefaultSnapshotEvery} {
+		dir := t.TempDir()
+		j, err := Open(dir, snapshotEvery, clk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := fill(t, j)
+		// The log is left as it is without Close,
+		// as if the server crashed.
+		j.log.Close()
+
+		j, err = Open(dir, snapshotEvery, clk)
+		if err != nil {
+			t.Fatalf("snapshot every %d: %v", snapshotEvery, err)
+		}
+		checkFilled(t, j, id)
+		if err = j.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTornLogTail(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	dir := t.TempDir()
+	j, err := Open(dir, 0, clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := fill(t, j)
+	j.log.Close()
+
+	f, err := os.OpenFile(filepath.Join(dir, logName), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.WriteString(`{"seq":100,"op":"addUs`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	j, err = Open(dir, 0, clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	checkFilled(t, j, id)
+}
+
+func TestCloseEmptiesLog(t *testing.T) {
+	dir := t.TempDir()
+	j, err := Open(dir, 0, clock.Real{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, j)
+	if err = j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, logName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("got log size %d after Close; want 0", info.Size())
+	}
+}
+
+func TestFailedLogWriteIsNotApplied(t *testing.T) {
+	j := openTestJournal(t, clock.Real{})
+	topics, err := j.UserTopicRepository().GetUserTopicRepository("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.log.Close()
+
+	if _, err = topics.AddTopic("MyTopic", nil); err == nil {
+		t.Fatal("got nil adding topic with closed log; want error")
+	}
+	all, err := topics.GetAllTopics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 0 {
+		t.Errorf("got %d topics; want 0", len(all))
+	}
+
+	if err = j.UserRepository().AddUser(&entity.User{Name: "alice"}); err == nil {
+		t.Fatal("got nil adding user with closed log; want error")
+	}
+	if _, err = j.UserRepository().GetUser("alice"); err == nil {
+		t.Error("got nil getting user; want error")
+	}
+}
@@ -0,0 +1,176 @@
+package journal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/inmem"
+)
+
+// The repositories below read from the in-memory ones. Every
+// change made through them is checked, logged and only then
+// applied to the in-memory repositories.
+
+type userRepository struct {
+	j *Journal
+}
+
+func (r *userRepository) AddUser(u *entity.User) error {
+	return r.j.change(func() (*entry, error) {
+		if u.Name == "" {
+			return nil, common.EmptyUserName("user name is empty")
+		}
+		if _, err := r.j.users.GetUser(u.Name); err == nil {
+			return nil, common.UserAlreadyExistsError(
+				fmt.Sprintf("user with name %s already exists", u.Name))
+		}
+		return &entry{Op: opAddUser, User: newUser(u)}, nil
+	})
+}
+
+func (r *userRepository) GetUser(name string) (*entity.User, error) {
+	return r.j.users.GetUser(name)
+}
+
+func (r *userRepository) RemoveUser(name string) error {
+	return r.j.change(func() (*entry, error) {
+		return &entry{Op: opRemoveUser, Name: name}, nil
+	})
+}
+
+type userTopicRepository struct {
+	j *Journal
+}
+
+func (r *userTopicRepository) GetUserTopicRepository(name string) (repo.TopicRepository, error) {
+	topics, err := r.j.topicRepository(name)
+	if err != nil {
+		return nil, err
+	}
+	return &topicRepository{j: r.j, name: name, topics: topics}, nil
+}
+
+type topicRepository struct {
+	j      *Journal
+	name   string
+	topics *inmem.InmemTopicRepository
+}
+
+func (r *topicRepository) AddTopic(title string, s entity.Scheduler) (int, error) {
+	var id int
+	err := r.j.change(func() (*entry, error) {
+		t, err := r.topics.NewTopic(title, s)
+		if err != nil {
+			return nil, err
+		}
+		id = t.Id
+		return r.putTopic(t), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *topicRepository) RemoveTopic(id int) error {
+	return r.j.change(func() (*entry, error) {
+		return &entry{Op: opRemoveTopic, Name: r.name, TopicId: id}, nil
+	})
+}
+
+func (r *topicRepository) GetAllTopics() ([]*entity.Topic, error) {
+	return r.topics.GetAllTopics()
+}
+
+func (r *topicRepository) GetDueTopics(now time.Time, limit int) ([]*entity.Topic, error) {
+	return r.topics.GetDueTopics(now, limit)
+}
+
+func (r *topicRepository) GetTopicById(id int) (*entity.Topic, error) {
+	return r.topics.GetTopicById(id)
+}
+
+func (r *topicRepository) UpdateTopic(t *entity.Topic) error {
+	return r.j.change(func() (*entry, error) {
+		if err := r.topics.CheckUpdate(t); err != nil {
+			return nil, err
+		}
+		return r.putTopic(t), nil
+	})
+}
+
+func (r *topicRepository) SuspendTopic(id int) error {
+	return r.modifyTopic(id, func(t *entity.Topic) {
+		t.Suspended = true
+	})
+}
+
+func (r *topicRepository) BuryTopic(id int, until time.Time) error {
+	return r.modifyTopic(id, func(t *entity.Topic) {
+		t.BuriedUntil = until
+	})
+}
+
+func (r *topicRepository) UnsuspendTopic(id int) error {
+	return r.modifyTopic(id, func(t *entity.Topic) {
+		t.Suspended = false
+		t.BuriedUntil = time.Time{}
+	})
+}
+
+// modifyTopic logs the topic as it is after modify changes it.
+func (r *topicRepository) modifyTopic(id int, modify func(t *entity.Topic)) error {
+	return r.j.change(func() (*entry, error) {
+		t, err := r.topics.GetTopicById(id)
+		if err != nil {
+			return nil, err
+		}
+		modify(t)
+		return r.putTopic(t), nil
+	})
+}
+
+func (r *topicRepository) putTopic(t *entity.Topic) *entry {
+	return &entry{Op: opPutTopic, Name: r.name, Topic: t}
+}
+
+type settingsRepository struct {
+	j *Journal
+}
+
+func (r *settingsRepository) GetSettings(name string) (*entity.Settings, error) {
+	return r.j.settings.GetSettings(name)
+}
+
+func (r *settingsRepository) SetSettings(name string, s *entity.Settings) error {
+	return r.j.change(func() (*entry, error) {
+		return &entry{Op: opSetSettings, Name: name, Settings: s}, nil
+	})
+}
+
+type reviewRepository struct {
+	j *Journal
+}
+
+func (r *reviewRepository) AddReview(name string, e *entity.ReviewEvent) error {
+	return r.j.change(func() (*entry, error) {
+		return &entry{Op: opAddReview, Name: name, Review: e}, nil
+	})
+}
+
+func (r *reviewRepository) GetReviews(name string) ([]*entity.ReviewEvent, error) {
+	return r.j.reviews.GetReviews(name)
+}
+
+func (r *reviewRepository) GetTopicReviews(name string, topicId int) ([]*entity.ReviewEvent, error) {
+	return r.j.reviews.GetTopicReviews(name, topicId)
+}
+
+func (r *reviewRepository) RemoveTopicReviews(name string, topicId int) error {
+	return r.j.change(func() (*entry, error) {
+		return &entry{Op: opRemoveTopicReviews, Name: name, TopicId: topicId}, nil
+	})
+}
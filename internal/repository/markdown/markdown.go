@@ -0,0 +1,194 @@
+// Package markdown keeps topics as Markdown files which can be
+// edited by hand. Topics of a user are kept in their directory,
+// one file named after the topic id per topic, e.g. 12.md.
+// The title and the scheduling fields are in the YAML frontmatter
+// and the rest of the file is free-form notes:
+//
+//	---
+//	title: Closures
+//	created: 2024-01-01T12:00:00Z
+//	lastRepeated: 2024-01-01T12:00:00Z
+//	nextRepeat: 2024-01-02T12:00:00Z
+//	level: 1
+//	interval: 24h0m0s
+//	---
+//	A closure is a function value that references
+//	variables from outside its body.
+//
+// Files changed outside of the server are reloaded.
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	topicExt = ".md"
+	// nextIdName is the file with the id of the next topic,
+	// so ids of removed topics are not reused.
+	nextIdName = ".next-id"
+)
+
+var delimiter = []byte("---\n")
+
+// frontmatter is the header of a topic file. Fields which are
+// left out when the file is written by hand get zero values,
+// so such a topic is due at once.
+type frontmatter struct {
+	Title        string        `yaml:"title"`
+	Created      time.Time     `yaml:"created"`
+	LastRepeated time.Time     `yaml:"lastRepeated"`
+	NextRepeat   time.Time     `yaml:"nextRepeat"`
+	Suspended    bool          `yaml:"suspended,omitempty"`
+	BuriedUntil  time.Time     `yaml:"buriedUntil,omitempty"`
+	Version      int           `yaml:"version,omitempty"`
+	Step         int           `yaml:"step,omitempty"`
+	Relearning   bool          `yaml:"relearning,omitempty"`
+	Lapses       int           `yaml:"lapses,omitempty"`
+	Level        entity.Level  `yaml:"level"`
+	Interval     time.Duration `yaml:"interval"`
+	EaseFactor   float64       `yaml:"easeFactor,omitempty"`
+	Repetitions  int           `yaml:"repetitions,omitempty"`
+	Stability    float64       `yaml:"stability,omitempty"`
+	Difficulty   float64       `yaml:"difficulty,omitempty"`
+	Box          int           `yaml:"box,omitempty"`
+}
+
+// formatTopic returns the content of the file of the topic
+// with the given notes.
+func formatTopic(t *entity.Topic, notes []byte) ([]byte, error) {
+	header, err := yaml.Marshal(&frontmatter{
+		Title:        t.Title,
+		Created:      t.Created,
+		LastRepeated: t.LastRepeated,
+		NextRepeat:   t.NextRepeat,
+		Suspended:    t.Suspended,
+		BuriedUntil:  t.BuriedUntil,
+		Version:      t.State.Version,
+		Step:         t.State.Step,
+		Relearning:   t.State.Relearning,
+		Lapses:       t.State.Lapses,
+		Level:        t.State.Level,
+		Interval:     t.State.Interval,
+		EaseFactor:   t.State.EaseFactor,
+		Repetitions:  t.State.Repetitions,
+		Stability:    t.State.Stability,
+		Difficulty:   t.State.Difficulty,
+		Box:          t.State.Box,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(delimiter)
+	buf.Write(header)
+	buf.Write(delimiter)
+	buf.Write(notes)
+	return buf.Bytes(), nil
+}
+
+// parseTopic parses the file of the topic with the given id
+// and returns the topic and its notes.
+func parseTopic(id int, data []byte) (*entity.Topic, []byte, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(data, delimiter) {
+		return nil, nil, errors.New("no frontmatter")
+	}
+	data = data[len(delimiter):]
+
+	var header, notes []byte
+	if bytes.HasPrefix(data, delimiter) {
+		notes = data[len(delimiter):]
+	} else {
+		i := bytes.Index(data, append([]byte("\n"), delimiter...))
+		if i < 0 {
+			return nil, nil, errors.New("frontmatter is not closed")
+		}
+		header, notes = data[:i+1], data[i+1+len(delimiter):]
+	}
+
+	var f frontmatter
+	if err := yaml.Unmarshal(header, &f); err != nil {
+		return nil, nil, err
+	}
+	if f.Title == "" {
+		return nil, nil, errors.New("title is empty")
+	}
+	return &entity.Topic{
+		Id:           id,
+		Title:        f.Title,
+		Created:      f.Created,
+		LastRepeated: f.LastRepeated,
+		NextRepeat:   f.NextRepeat,
+		Suspended:    f.Suspended,
+		BuriedUntil:  f.BuriedUntil,
+		State: entity.ReviewState{
+			Version:     f.Version,
+			Step:        f.Step,
+			Relearning:  f.Relearning,
+			Lapses:      f.Lapses,
+			Level:       f.Level,
+			Interval:    f.Interval,
+			EaseFactor:  f.EaseFactor,
+			Repetitions: f.Repetitions,
+			Stability:   f.Stability,
+			Difficulty:  f.Difficulty,
+			Box:         f.Box,
+		},
+	}, notes, nil
+}
+
+// topicFile returns the name of the file of the topic with the given id.
+func topicFile(id int) string {
+	return strconv.Itoa(id) + topicExt
+}
+
+// topicId returns the id of the topic kept in the file
+// with the given name and whether it is a topic file.
+func topicId(name string) (int, bool) {
+	base, ok := strings.CutSuffix(filepath.Base(name), topicExt)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(base)
+	if err != nil || id <= 0 || strconv.Itoa(id) != base {
+		return 0, false
+	}
+	return id, true
+}
+
+// writeFile replaces the file at once, so the watcher
+// and editors never see it half written.
+func writeFile(path string, data []byte) error {
+	dir, name := filepath.Split(path)
+	f, err := os.CreateTemp(dir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
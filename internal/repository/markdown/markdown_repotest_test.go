@@ -0,0 +1,28 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/repotest"
+)
+
+func TestMarkdownTopicRepositoryContract(t *testing.T) {
+	repotest.TestTopicRepository(t, func(t *testing.T, clk clock.Clock) repo.TopicRepository {
+		r, err := NewMarkdownTopicRepository(t.TempDir(), clk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { r.Close() })
+		return r
+	})
+}
+
+func TestMarkdownUserTopicRepositoryContract(t *testing.T) {
+	root := t.TempDir()
+	repotest.TestUserTopicRepository(t, func(t *testing.T, clk clock.Clock) (repo.UserTopicRepository, func()) {
+		r := NewMarkdownUserTopicRepository(root, clk)
+		return r, func() { r.Close() }
+	})
+}
@@ -0,0 +1,334 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// MarkdownTopicRepository is an implementation of topics repository
+// which keeps every topic in a Markdown file. The topics are also kept
+// in memory and reloaded when their files change. It is safe for
+// concurent use by multiple goroutines.
+type MarkdownTopicRepository struct {
+	m           sync.Mutex
+	dir         string
+	topics      map[int]*entity.Topic
+	notes       map[int][]byte
+	topicTitles map[string]int
+	nextId      int
+	clock       clock.Clock
+
+	watcher *watcher
+	// ownWatcher is set if the watcher is not shared
+	// with other repositories and closed with this one.
+	ownWatcher bool
+}
+
+// NewMarkdownTopicRepository loads the topics from the directory,
+// creating it if needed, and watches it for changes until Close.
+func NewMarkdownTopicRepository(dir string, clk clock.Clock) (*MarkdownTopicRepository, error) {
+	w, err := newWatcher()
+	if err != nil {
+		return nil, err
+	}
+	ts, err := openTopicRepository(dir, clk, w)
+	if err != nil {
+		w.close()
+		return nil, err
+	}
+	ts.ownWatcher = true
+	return ts, nil
+}
+
+// openTopicRepository loads the topics from the directory,
+// creating it if needed, and watches it with w.
+func openTopicRepository(dir string, clk clock.Clock, w *watcher) (*MarkdownTopicRepository, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	ts := &MarkdownTopicRepository{
+		dir:         dir,
+		topics:      make(map[int]*entity.Topic),
+		notes:       make(map[int][]byte),
+		topicTitles: make(map[string]int),
+		nextId:      1,
+		clock:       clk,
+		watcher:     w,
+	}
+
+	// The directory is watched first, so the files
+	// changed while loading are reloaded.
+	if err := w.add(ts); err != nil {
+		return nil, err
+	}
+	if err := ts.load(); err != nil {
+		w.remove(ts)
+		return nil, err
+	}
+	return ts, nil
+}
+
+// Close stops watching the directory.
+func (ts *MarkdownTopicRepository) Close() error {
+	if ts.ownWatcher {
+		return ts.watcher.close()
+	}
+	ts.watcher.remove(ts)
+	return nil
+}
+
+func (ts *MarkdownTopicRepository) AddTopic(title string, s entity.Scheduler) (int, error) {
+	if title == "" {
+		return 0, common.TopicTitleError("topic's title is empty")
+	}
+
+	ts.m.Lock()
+	defer ts.m.Unlock()
+
+	if _, ok := ts.topicTitles[title]; ok {
+		return 0, common.TopicTitleConflictError(fmt.Sprintf(
+			"topic %s already exists",
+			title,
+		))
+	}
+
+	topic := entity.NewTopic(ts.nextId, title, ts.clock.Now(), s)
+	// The next id is saved first, so the id is not
+	// given again even if saving the topic fails.
+	err := writeFile(filepath.Join(ts.dir, nextIdName), []byte(strconv.Itoa(ts.nextId+1)))
+	if err != nil {
+		return 0, err
+	}
+	ts.nextId++
+	if err = ts.putTopic(topic); err != nil {
+		return 0, err
+	}
+	return topic.Id, nil
+}
+
+func (ts *MarkdownTopicRepository) RemoveTopic(id int) error {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	if _, ok := ts.topics[id]; !ok {
+		return nil
+	}
+	err := os.Remove(filepath.Join(ts.dir, topicFile(id)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	ts.forget(id)
+	return nil
+}
+
+func (ts *MarkdownTopicRepository) GetAllTopics() ([]*entity.Topic, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	res := make([]*entity.Topic, 0, len(ts.topics))
+	for _, t := range ts.topics {
+		topic := *t
+		res = append(res, &topic)
+	}
+	return res, nil
+}
+
+func (ts *MarkdownTopicRepository) GetDueTopics(now time.Time, limit int) ([]*entity.Topic, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	// The files are meant for a person to keep by hand,
+	// so there are few enough of them to look through.
+	res := make([]*entity.Topic, 0)
+	for _, t := range ts.topics {
+		if t.Active(now) && !t.NextRepeat.After(now) {
+			topic := *t
+			res = append(res, &topic)
+		}
+	}
+	slices.SortFunc(res, func(a, b *entity.Topic) int {
+		if c := a.NextRepeat.Compare(b.NextRepeat); c != 0 {
+			return c
+		}
+		return a.Id - b.Id
+	})
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (ts *MarkdownTopicRepository) GetTopicById(id int) (*entity.Topic, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	t, ok := ts.topics[id]
+	if !ok {
+		return nil, common.TopicNotExistsError(
+			fmt.Sprintf("topic with id %d does not exist", id))
+	}
+	topic := *t
+	return &topic, nil
+}
+
+func (ts *MarkdownTopicRepository) UpdateTopic(t *entity.Topic) error {
+	if t.Title == "" {
+		return common.TopicTitleError("topic's title is empty")
+	}
+	return ts.modifyTopic(t.Id, func(topic *entity.Topic) {
+		*topic = *t
+	})
+}
+
+func (ts *MarkdownTopicRepository) SuspendTopic(id int) error {
+	return ts.modifyTopic(id, func(topic *entity.Topic) {
+		topic.Suspended = true
+	})
+}
+
+func (ts *MarkdownTopicRepository) BuryTopic(id int, until time.Time) error {
+	return ts.modifyTopic(id, func(topic *entity.Topic) {
+		topic.BuriedUntil = until
+	})
+}
+
+func (ts *MarkdownTopicRepository) UnsuspendTopic(id int) error {
+	return ts.modifyTopic(id, func(topic *entity.Topic) {
+		topic.Suspended = false
+		topic.BuriedUntil = time.Time{}
+	})
+}
+
+func (ts *MarkdownTopicRepository) modifyTopic(id int, modify func(*entity.Topic)) error {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	old, ok := ts.topics[id]
+	if !ok {
+		return common.TopicNotExistsError(
+			fmt.Sprintf("topic with id %d does not exist", id))
+	}
+	topic := *old
+	modify(&topic)
+	if other, ok := ts.topicTitles[topic.Title]; ok && other != id {
+		return common.TopicTitleConflictError(fmt.Sprintf(
+			"topic %s already exists",
+			topic.Title,
+		))
+	}
+	return ts.putTopic(&topic)
+}
+
+// putTopic writes the file of the topic keeping its notes
+// and replaces the topic in memory. It must be called with ts.m held.
+func (ts *MarkdownTopicRepository) putTopic(t *entity.Topic) error {
+	data, err := formatTopic(t, ts.notes[t.Id])
+	if err != nil {
+		return err
+	}
+	if err = writeFile(filepath.Join(ts.dir, topicFile(t.Id)), data); err != nil {
+		return err
+	}
+	ts.remember(t, ts.notes[t.Id])
+	return nil
+}
+
+// remember replaces the topic in memory.
+// It must be called with ts.m held.
+func (ts *MarkdownTopicRepository) remember(t *entity.Topic, notes []byte) {
+	ts.forget(t.Id)
+	ts.topics[t.Id] = t
+	ts.notes[t.Id] = notes
+	// Titles changed by hand may repeat. The topic which
+	// has the title first keeps it in the index.
+	if _, ok := ts.topicTitles[t.Title]; !ok {
+		ts.topicTitles[t.Title] = t.Id
+	}
+	ts.nextId = max(ts.nextId, t.Id+1)
+}
+
+// forget removes the topic from memory.
+// It must be called with ts.m held.
+func (ts *MarkdownTopicRepository) forget(id int) {
+	t, ok := ts.topics[id]
+	if !ok {
+		return
+	}
+	delete(ts.topics, id)
+	delete(ts.notes, id)
+	if ts.topicTitles[t.Title] == id {
+		delete(ts.topicTitles, t.Title)
+		// Give the title to another topic which has it.
+		for _, other := range ts.topics {
+			if other.Title == t.Title {
+				ts.topicTitles[t.Title] = other.Id
+				break
+			}
+		}
+	}
+}
+
+// load reads all topic files and the next id. Files which cannot
+// be read are logged and skipped, so a broken note does not make
+// the other topics unavailable. It is loaded once it is fixed.
+func (ts *MarkdownTopicRepository) load() error {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+
+	path := filepath.Join(ts.dir, nextIdName)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		nextId, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			// The ids of the loaded topics are still not reused.
+			log.Printf("%s: %v", path, err)
+		} else {
+			ts.nextId = nextId
+		}
+	}
+
+	entries, err := os.ReadDir(ts.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if id, ok := topicId(e.Name()); ok && e.Type().IsRegular() {
+			if err = ts.reload(id); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+	return nil
+}
+
+// reload reads the file of the topic with the given id again.
+// The topic is forgotten if the file is removed. It must be
+// called with ts.m held.
+func (ts *MarkdownTopicRepository) reload(id int) error {
+	path := filepath.Join(ts.dir, topicFile(id))
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		ts.forget(id)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	t, notes, err := parseTopic(id, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	ts.remember(t, notes)
+	return nil
+}
@@ -0,0 +1,208 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// openTestRepository opens the repository in the directory
+// and closes it at the end of the test.
+func openTestRepository(t *testing.T, dir string, clk clock.Clock) *MarkdownTopicRepository {
+	r, err := NewMarkdownTopicRepository(dir, clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+// eventually waits until check returns true
+// for the watcher to notice a change.
+func eventually(t *testing.T, check func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !check() {
+		if time.Now().After(deadline) {
+			t.Fatal("gave up waiting for the change to be reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReloadExternalEdits(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	r := openTestRepository(t, dir, clock.NewFake(now))
+
+	id, err := r.AddTopic("MyTopic", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Notes are added and the topic is renamed by hand.
+	path := filepath.Join(dir, topicFile(id))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), "title: MyTopic", "title: Renamed", 1))
+	data = append(data, "Some notes\n"...)
+	if err = os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		topic, err := r.GetTopicById(id)
+		return err == nil && topic.Title == "Renamed"
+	})
+	if _, err = r.AddTopic("MyTopic", nil); err != nil {
+		t.Errorf("got %v; want the old title free", err)
+	}
+
+	// The notes are kept when the topic is changed.
+	if err = r.SuspendTopic(id); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "---\nSome notes\n") {
+		t.Errorf("got file\n%s\nwant the notes kept", data)
+	}
+
+	// A topic written by hand with the title only is due at once.
+	err = os.WriteFile(filepath.Join(dir, "100.md"), []byte("---\ntitle: Handmade\n---\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		due, _ := r.GetDueTopics(now, 0)
+		return slices.ContainsFunc(due, func(t *entity.Topic) bool { return t.Id == 100 })
+	})
+	if next, err := r.AddTopic("AfterHandmade", nil); err != nil || next != 101 {
+		t.Errorf("got id %d, error %v; want 101", next, err)
+	}
+
+	// A broken file leaves the topic as it was.
+	if err = os.WriteFile(path, []byte("no frontmatter"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Remove(filepath.Join(dir, "100.md")); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		_, err := r.GetTopicById(100)
+		return err != nil
+	})
+	if topic, err := r.GetTopicById(id); err != nil || topic.Title != "Renamed" {
+		t.Errorf("got %v, error %v; want the topic kept", topic, err)
+	}
+}
+
+func TestLoadSkipsBrokenFiles(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	r := openTestRepository(t, dir, clock.NewFake(now))
+	id, err := r.AddTopic("MyTopic", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "100.md"), []byte("no frontmatter"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, nextIdName), []byte("broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r = openTestRepository(t, dir, clock.NewFake(now))
+	if topic, err := r.GetTopicById(id); err != nil || topic.Title != "MyTopic" {
+		t.Errorf("got %v, error %v; want the topic loaded", topic, err)
+	}
+	if _, err = r.GetTopicById(100); err == nil {
+		t.Error("got the broken topic loaded; want it skipped")
+	}
+	if next, err := r.AddTopic("Next", nil); err != nil || next != id+1 {
+		t.Errorf("got id %d, error %v; want %d", next, err, id+1)
+	}
+}
+
+func TestUserDirectoriesShareWatcher(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	root := t.TempDir()
+	r := NewMarkdownUserTopicRepository(root, clock.NewFake(now))
+	t.Cleanup(func() { r.Close() })
+
+	if _, err := r.GetUserTopicRepository(".hidden"); err == nil {
+		t.Error("got a repository for .hidden; want an error")
+	}
+	for _, name := range []string{"alice", "bob"} {
+		topics, err := r.GetUserTopicRepository(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = topics.AddTopic("MyTopic", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.userTopicRepo["alice"].watcher != r.userTopicRepo["bob"].watcher {
+		t.Fatal("got a watcher per user; want one shared")
+	}
+
+	// The edit is reloaded by the repository of the user only.
+	path := filepath.Join(root, "bob", topicFile(1))
+	err := os.WriteFile(path, []byte("---\ntitle: Renamed\n---\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, alice := r.userTopicRepo["bob"], r.userTopicRepo["alice"]
+	eventually(t, func() bool {
+		topic, err := bob.GetTopicById(1)
+		return err == nil && topic.Title == "Renamed"
+	})
+	if topic, err := alice.GetTopicById(1); err != nil || topic.Title != "MyTopic" {
+		t.Errorf("got %v, error %v; want alice's topic unchanged", topic, err)
+	}
+}
+
+func TestParseTopic(t *testing.T) {
+	topic := &entity.Topic{
+		Id:         1,
+		Title:      "Title: with a colon",
+		Created:    time.Date(2024, 1, 1, 12, 0, 0, 5, time.UTC),
+		NextRepeat: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+		State:      entity.ReviewState{Version: 1, Level: 2, EaseFactor: 2.5, Interval: time.Hour},
+	}
+	notes := []byte("# Notes\n\n---\nA rule is not the end of the frontmatter.\n")
+	data, err := formatTopic(topic, notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, gotNotes, err := parseTopic(1, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != topic.Title || !got.Created.Equal(topic.Created) ||
+		!got.NextRepeat.Equal(topic.NextRepeat) || got.State != topic.State {
+		t.Errorf("got %+v; want %+v", got, topic)
+	}
+	if string(gotNotes) != string(notes) {
+		t.Errorf("got notes %q; want %q", gotNotes, notes)
+	}
+
+	for _, bad := range []string{"", "title: x\n", "---\ntitle: x\n", "---\ncreated: 2024-01-01T00:00:00Z\n---\n"} {
+		if _, _, err = parseTopic(1, []byte(bad)); err == nil {
+			t.Errorf("got nil parsing %q; want error", bad)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+)
+
+// MarkdownUserTopicRepository keeps topics of every user in
+// a directory named after the user. It is safe for concurent
+// use by multiple goroutines.
+type MarkdownUserTopicRepository struct {
+	m             sync.Mutex
+	root          string
+	userTopicRepo map[string]*MarkdownTopicRepository
+	// watcher watches the directories of all users.
+	// It is created with the first repository.
+	watcher *watcher
+	clock   clock.Clock
+}
+
+func NewMarkdownUserTopicRepository(root string, clk clock.Clock) *MarkdownUserTopicRepository {
+	return &MarkdownUserTopicRepository{
+		root:          root,
+		userTopicRepo: make(map[string]*MarkdownTopicRepository),
+		clock:         clk,
+	}
+}
+
+func (r *MarkdownUserTopicRepository) GetUserTopicRepository(name string) (repo.TopicRepository, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	topicRepo, ok := r.userTopicRepo[name]
+	if ok {
+		return topicRepo, nil
+	}
+
+	if err := CheckUserName(name); err != nil {
+		return nil, err
+	}
+	if r.watcher == nil {
+		w, err := newWatcher()
+		if err != nil {
+			return nil, err
+		}
+		r.watcher = w
+	}
+	topicRepo, err := openTopicRepository(
+		filepath.Join(r.root, url.PathEscape(name)), r.clock, r.watcher)
+	if err != nil {
+		return nil, err
+	}
+	r.userTopicRepo[name] = topicRepo
+	return topicRepo, nil
+}
+
+// Close stops watching directories of all users.
+func (r *MarkdownUserTopicRepository) Close() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	var errs []error
+	for name, topicRepo := range r.userTopicRepo {
+		errs = append(errs, topicRepo.Close())
+		delete(r.userTopicRepo, name)
+	}
+	if r.watcher != nil {
+		errs = append(errs, r.watcher.close())
+		r.watcher = nil
+	}
+	return errors.Join(errs...)
+}
+
+// CheckUserName returns an error if the user's topics
+// cannot be kept in a directory named after the user.
+func CheckUserName(name string) error {
+	// Names starting with a dot would be hidden
+	// or refer to the root and its parent.
+	if name == "" || strings.HasPrefix(name, ".") {
+		return common.InvalidAuthData(fmt.Sprintf(
+			"user name %q cannot be a directory name", name))
+	}
+	return nil
+}
+
+// checkedUserRepository refuses to add users
+// whose names CheckUserName does not accept.
+type checkedUserRepository struct {
+	repo.UserRepository
+}
+
+// WithUserNameCheck returns the users which refuses to add users
+// whose topics cannot be kept in a MarkdownUserTopicRepository,
+// so the name is checked at registration rather than when the
+// user's topics are first accessed.
+func WithUserNameCheck(users repo.UserRepository) repo.UserRepository {
+	return checkedUserRepository{users}
+}
+
+func (r checkedUserRepository) AddUser(u *entity.User) error {
+	if err := CheckUserName(u.Name); err != nil {
+		return err
+	}
+	return r.UserRepository.AddUser(u)
+}
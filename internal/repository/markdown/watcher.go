@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"log"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watcher watches the directories of topic repositories with one
+// fsnotify watcher, so the number of users is not limited by the
+// number of inotify instances, and routes the events by directory.
+type watcher struct {
+	w    *fsnotify.Watcher
+	done chan struct{}
+
+	m     sync.Mutex
+	repos map[string]*MarkdownTopicRepository
+}
+
+func newWatcher() (*watcher, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	wt := &watcher{
+		w:     w,
+		done:  make(chan struct{}),
+		repos: make(map[string]*MarkdownTopicRepository),
+	}
+	go wt.run()
+	return wt, nil
+}
+
+// add starts watching the directory of the repository.
+func (wt *watcher) add(ts *MarkdownTopicRepository) error {
+	dir := filepath.Clean(ts.dir)
+	wt.m.Lock()
+	wt.repos[dir] = ts
+	wt.m.Unlock()
+	err := wt.w.Add(dir)
+	if err != nil {
+		wt.remove(ts)
+	}
+	return err
+}
+
+// remove stops watching the directory of the repository.
+func (wt *watcher) remove(ts *MarkdownTopicRepository) {
+	dir := filepath.Clean(ts.dir)
+	wt.m.Lock()
+	delete(wt.repos, dir)
+	wt.m.Unlock()
+	// The directory is not watched if add failed.
+	wt.w.Remove(dir)
+}
+
+// close stops watching all directories.
+func (wt *watcher) close() error {
+	err := wt.w.Close()
+	<-wt.done
+	return err
+}
+
+// run reloads the topic files changed outside of the repositories.
+// The files written by the repositories themselves are reloaded
+// too, which changes nothing.
+func (wt *watcher) run() {
+	defer close(wt.done)
+	for {
+		select {
+		case event, ok := <-wt.w.Events:
+			if !ok {
+				return
+			}
+			id, ok := topicId(event.Name)
+			if !ok {
+				continue
+			}
+			wt.m.Lock()
+			ts := wt.repos[filepath.Dir(event.Name)]
+			wt.m.Unlock()
+			if ts == nil {
+				continue
+			}
+			ts.m.Lock()
+			err := ts.reload(id)
+			ts.m.Unlock()
+			if err != nil {
+				// The topic stays as it was until
+				// the file is fixed.
+				log.Println(err)
+			}
+		case err, ok := <-wt.w.Errors:
+			if !ok {
+				return
+			}
+			log.Println(err)
+		}
+	}
+}
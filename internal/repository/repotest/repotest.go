@@ -0,0 +1,67 @@
+// Package repotest checks that implementations of the repository
+// interfaces follow the same contract. Tests of every backend
+// call its functions with constructors of their repositories.
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+)
+
+// start is the time of the fake clock the topic repositories
+// are created with. It has no fraction of a second, so backends
+// which store times with less precision still return it exactly.
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// firstStep is the interval before the first repetition
+// of the topics added by the tests.
+const firstStep = 20 * time.Minute
+
+// sched is the scheduler the topics are added with.
+var sched entity.Scheduler = firstStepScheduler{}
+
+// firstStepScheduler makes new topics due after firstStep
+// and repeats them with the same interval.
+type firstStepScheduler struct{}
+
+func (firstStepScheduler) Init(now time.Time) (entity.ReviewState, time.Time) {
+	return entity.ReviewState{Version: entity.ReviewStateVersion, Interval: firstStep}, now.Add(firstStep)
+}
+
+func (firstStepScheduler) Schedule(t entity.Topic, _ entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	return t.State, now.Add(t.State.Interval)
+}
+
+// NewTopicRepository returns an empty topic repository which
+// takes the current time from clk. Resources of the repository
+// have to be released with t.Cleanup.
+type NewTopicRepository func(t *testing.T, clk clock.Clock) repo.TopicRepository
+
+// OpenUserTopicRepository opens the user topic repository of a
+// storage which is kept between the calls made by the same test, so
+// the first call gets an empty storage and the following ones get
+// what was stored before. The storage is closed with close.
+type OpenUserTopicRepository func(t *testing.T, clk clock.Clock) (r repo.UserTopicRepository, close func())
+
+// NewUserRepository returns an empty user repository.
+type NewUserRepository func(t *testing.T) repo.UserRepository
+
+// NewSettingsRepository returns an empty settings repository.
+type NewSettingsRepository func(t *testing.T) repo.SettingsRepository
+
+// NewReviewRepository returns an empty review repository.
+type NewReviewRepository func(t *testing.T) repo.ReviewRepository
+
+// wantError reports an error unless err is of the type T.
+func wantError[T error](t *testing.T, err error) {
+	t.Helper()
+	var target T
+	if !errors.As(err, &target) {
+		t.Errorf("got error %v (%T); want %T", err, err, target)
+	}
+}
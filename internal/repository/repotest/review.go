@@ -0,0 +1,60 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// TestReviewRepository checks the contract of repository.ReviewRepository.
+func TestReviewRepository(t *testing.T, newRepo NewReviewRepository) {
+	r := newRepo(t)
+
+	events := []*entity.ReviewEvent{
+		{TopicId: 1, Time: start, Grade: entity.GradeGood, NewInterval: time.Hour},
+		{TopicId: 2, Time: start.Add(time.Minute), Grade: entity.GradeHard},
+		{TopicId: 1, Time: start.Add(time.Hour), Grade: entity.GradeWrong, Elapsed: time.Hour},
+	}
+	for _, e := range events {
+		if err := r.AddReview("User", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.AddReview("OtherUser", &entity.ReviewEvent{TopicId: 1, Time: start})
+
+	all, err := r.GetReviews("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(events) {
+		t.Fatalf("got %d reviews; want %d", len(all), len(events))
+	}
+	for i, e := range all {
+		if e.TopicId != events[i].TopicId || !e.Time.Equal(events[i].Time) ||
+			e.Grade != events[i].Grade || e.NewInterval != events[i].NewInterval ||
+			e.Elapsed != events[i].Elapsed {
+			t.Errorf("got review %d = %+v; want %+v", i, e, events[i])
+		}
+	}
+
+	topicReviews, err := r.GetTopicReviews("User", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topicReviews) != 2 || topicReviews[1].Grade != entity.GradeWrong {
+		t.Errorf("got %d reviews of topic 1; want 2 in order", len(topicReviews))
+	}
+
+	if err = r.RemoveTopicReviews("User", 1); err != nil {
+		t.Fatal(err)
+	}
+	all, _ = r.GetReviews("User")
+	if len(all) != 1 || all[0].TopicId != 2 {
+		t.Errorf("got %d reviews after removal; want only the one of topic 2", len(all))
+	}
+	other, _ := r.GetReviews("OtherUser")
+	if len(other) != 1 {
+		t.Errorf("got %d reviews of another user; want 1", len(other))
+	}
+}
@@ -0,0 +1,45 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// TestSettingsRepository checks the contract of repository.SettingsRepository.
+func TestSettingsRepository(t *testing.T, newRepo NewSettingsRepository) {
+	r := newRepo(t)
+
+	s, err := r.GetSettings("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Scheduler != "" || s.Vacation != nil {
+		t.Errorf("got %+v; want default settings", s)
+	}
+
+	s.Scheduler = "sm2"
+	s.LearningSteps = []entity.Duration{entity.Duration(time.Minute)}
+	s.Vacation = &entity.Vacation{Start: start, End: start.Add(time.Hour), Mode: entity.VacationShift}
+	if err = r.SetSettings("User", s); err != nil {
+		t.Fatal(err)
+	}
+	// Changing the saved settings does not change the stored ones.
+	s.LearningSteps[0] = 0
+
+	got, err := r.GetSettings("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Scheduler != "sm2" || len(got.LearningSteps) != 1 ||
+		got.LearningSteps[0] != entity.Duration(time.Minute) ||
+		got.Vacation == nil || !got.Vacation.End.Equal(start.Add(time.Hour)) {
+		t.Errorf("got %+v; want the saved settings", got)
+	}
+
+	other, _ := r.GetSettings("OtherUser")
+	if other.Scheduler != "" {
+		t.Errorf("got scheduler %q for another user; want default", other.Scheduler)
+	}
+}
@@ -0,0 +1,370 @@
+package repotest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+)
+
+// TestTopicRepository checks the contract of repository.TopicRepository.
+func TestTopicRepository(t *testing.T, newRepo NewTopicRepository) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, r repo.TopicRepository)
+	}{
+		{"AddTopic", testAddTopic},
+		{"AddTopicWithEmptyTitle", testAddTopicWithEmptyTitle},
+		{"AddTopicWithSameTitleTwice", testAddTopicWithSameTitleTwice},
+		{"RemoveTopic", testRemoveTopic},
+		{"GetAllTopics", testGetAllTopics},
+		{"UpdateTopic", testUpdateTopic},
+		{"ReturnedTopicsAreCopies", testReturnedTopicsAreCopies},
+		{"SuspendAndBuryTopic", testSuspendAndBuryTopic},
+		{"GetDueTopics", testGetDueTopics},
+		{"ConcurrentAddTopic", testConcurrentAddTopic},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, newRepo(t, clock.NewFake(start)))
+		})
+	}
+}
+
+func testAddTopic(t *testing.T, r repo.TopicRepository) {
+	for i, title := range []string{"MyTopic1", "MyTopic2", "MyTopic3"} {
+		id, err := r.AddTopic(title, sched)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Ids are assigned in order starting from 1.
+		if id != i+1 {
+			t.Errorf("got id %d for %s; want %d", id, title, i+1)
+		}
+	}
+
+	topic, err := r.GetTopicById(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topic.Id != 2 || topic.Title != "MyTopic2" {
+		t.Errorf("got topic %d %q; want 2 \"MyTopic2\"", topic.Id, topic.Title)
+	}
+	if !topic.Created.Equal(start) {
+		t.Errorf("got topic.Created = %s; want %s", topic.Created, start)
+	}
+	if got := topic.NextRepeat.Sub(start); got != firstStep {
+		t.Errorf("got first interval %s; want %s", got, firstStep)
+	}
+	if !topic.Active(start) {
+		t.Errorf("got new topic inactive; want active")
+	}
+
+	_, err = r.GetTopicById(100)
+	wantError[common.TopicNotExistsError](t, err)
+
+	// Without a scheduler a topic is due at once.
+	id, err := r.AddTopic("Unscheduled", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, err = r.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !topic.NextRepeat.Equal(start) {
+		t.Errorf("got topic.NextRepeat = %s; want %s", topic.NextRepeat, start)
+	}
+}
+
+func testAddTopicWithEmptyTitle(t *testing.T, r repo.TopicRepository) {
+	_, err := r.AddTopic("", sched)
+	wantError[common.TopicTitleError](t, err)
+
+	// A failed addition does not use up an id.
+	id, err := r.AddTopic("MyTopic", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 1 {
+		t.Errorf("got id %d; want 1", id)
+	}
+}
+
+func testAddTopicWithSameTitleTwice(t *testing.T, r repo.TopicRepository) {
+	if _, err := r.AddTopic("MyTopic", sched); err != nil {
+		t.Fatal(err)
+	}
+	_, err := r.AddTopic("MyTopic", sched)
+	wantError[common.TopicTitleConflictError](t, err)
+
+	id, err := r.AddTopic("OtherTopic", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 2 {
+		t.Errorf("got id %d; want 2", id)
+	}
+}
+
+func testRemoveTopic(t *testing.T, r repo.TopicRepository) {
+	r.AddTopic("MyTopic1", sched)
+	id, err := r.AddTopic("MyTopic2", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.RemoveTopic(id); err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.GetTopicById(id)
+	wantError[common.TopicNotExistsError](t, err)
+
+	// Removing a missing topic is not an error.
+	if err = r.RemoveTopic(id); err != nil {
+		t.Errorf("got %v; want nil", err)
+	}
+
+	// The title is free again, but the id is not reused.
+	newId, err := r.AddTopic("MyTopic2", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newId == id {
+		t.Errorf("got id %d of the removed topic; want a new one", newId)
+	}
+}
+
+func testGetAllTopics(t *testing.T, r repo.TopicRepository) {
+	topics, err := r.GetAllTopics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 0 {
+		t.Errorf("got %d topics; want 0", len(topics))
+	}
+
+	want := map[string]bool{"MyTopic1": true, "MyTopic2": true, "MyTopic3": true}
+	for title := range want {
+		r.AddTopic(title, sched)
+	}
+	topics, err = r.GetAllTopics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != len(want) {
+		t.Errorf("got %d topics; want %d", len(topics), len(want))
+	}
+	for _, topic := range topics {
+		if !want[topic.Title] {
+			t.Errorf("got unexpected topic %q", topic.Title)
+		}
+	}
+}
+
+func testUpdateTopic(t *testing.T, r repo.TopicRepository) {
+	id, err := r.AddTopic("MyTopic", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AddTopic("OtherTopic", sched)
+
+	topic, err := r.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic.Title = "RenamedTopic"
+	topic.State.Repetitions = 3
+	topic.State.Interval = 48 * time.Hour
+	topic.LastRepeated = start.Add(time.Hour)
+	topic.NextRepeat = start.Add(49 * time.Hour)
+	if err = r.UpdateTopic(topic); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != topic.Title || got.State != topic.State ||
+		!got.LastRepeated.Equal(topic.LastRepeated) || !got.NextRepeat.Equal(topic.NextRepeat) {
+		t.Errorf("got %+v; want %+v", got, topic)
+	}
+
+	// The old title is free after renaming.
+	if _, err = r.AddTopic("MyTopic", sched); err != nil {
+		t.Errorf("got %v; want the old title free", err)
+	}
+
+	topic.Title = "OtherTopic"
+	wantError[common.TopicTitleConflictError](t, r.UpdateTopic(topic))
+	topic.Title = ""
+	wantError[common.TopicTitleError](t, r.UpdateTopic(topic))
+	topic.Title = "RenamedTopic"
+	topic.Id = 100
+	wantError[common.TopicNotExistsError](t, r.UpdateTopic(topic))
+}
+
+func testReturnedTopicsAreCopies(t *testing.T, r repo.TopicRepository) {
+	id, err := r.AddTopic("MyTopic", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topic, _ := r.GetTopicById(id)
+	topic.Suspended = true
+	all, _ := r.GetAllTopics()
+	all[0].Title = "Changed"
+	due, _ := r.GetDueTopics(start.Add(time.Hour), 0)
+	due[0].NextRepeat = start.Add(time.Hour)
+
+	topic, err = r.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topic.Suspended || topic.Title != "MyTopic" || !topic.NextRepeat.Equal(start.Add(firstStep)) {
+		t.Errorf("got %+v changed through a returned topic; want it unchanged", topic)
+	}
+}
+
+func testSuspendAndBuryTopic(t *testing.T, r repo.TopicRepository) {
+	id, err := r.AddTopic("MyTopic", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = r.SuspendTopic(id); err != nil {
+		t.Fatal(err)
+	}
+	topic, _ := r.GetTopicById(id)
+	if topic.Active(start) {
+		t.Errorf("got suspended topic active; want inactive")
+	}
+
+	if err = r.UnsuspendTopic(id); err != nil {
+		t.Fatal(err)
+	}
+	topic, _ = r.GetTopicById(id)
+	if !topic.Active(start) {
+		t.Errorf("got unsuspended topic inactive; want active")
+	}
+
+	tomorrow := start.Add(12 * time.Hour)
+	if err = r.BuryTopic(id, tomorrow); err != nil {
+		t.Fatal(err)
+	}
+	topic, _ = r.GetTopicById(id)
+	if topic.Active(start) {
+		t.Errorf("got buried topic active; want inactive")
+	}
+	if !topic.Active(tomorrow) {
+		t.Errorf("got buried topic inactive after burial; want active")
+	}
+
+	wantError[common.TopicNotExistsError](t, r.SuspendTopic(100))
+	wantError[common.TopicNotExistsError](t, r.BuryTopic(100, tomorrow))
+	wantError[common.TopicNotExistsError](t, r.UnsuspendTopic(100))
+}
+
+func testGetDueTopics(t *testing.T, r repo.TopicRepository) {
+	now := start.Add(24 * time.Hour)
+	due := map[string]time.Duration{
+		"Late":      -time.Hour,
+		"Early":     -2 * time.Hour,
+		"Now":       0,
+		"Future":    time.Hour,
+		"Suspended": -3 * time.Hour,
+		"Buried":    -3 * time.Hour,
+	}
+	for _, title := range []string{"Late", "Early", "Now", "Future", "Suspended", "Buried"} {
+		id, err := r.AddTopic(title, sched)
+		if err != nil {
+			t.Fatal(err)
+		}
+		topic, _ := r.GetTopicById(id)
+		topic.NextRepeat = now.Add(due[title])
+		if err = r.UpdateTopic(topic); err != nil {
+			t.Fatal(err)
+		}
+		switch title {
+		case "Suspended":
+			r.SuspendTopic(id)
+		case "Buried":
+			r.BuryTopic(id, now.Add(time.Hour))
+		}
+	}
+
+	want := []string{"Early", "Late", "Now"}
+	for _, limit := range []int{0, 2, 10} {
+		topics, err := r.GetDueTopics(now, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := len(want)
+		if limit > 0 {
+			n = min(n, limit)
+		}
+		var got []string
+		for _, topic := range topics {
+			got = append(got, topic.Title)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want[:n]) {
+			t.Errorf("limit %d: got %v; want %v", limit, got, want[:n])
+		}
+	}
+}
+
+func testConcurrentAddTopic(t *testing.T, r repo.TopicRepository) {
+	const workers, perWorker = 8, 10
+
+	var wg sync.WaitGroup
+	ids := make(chan int, workers*perWorker)
+	var sameTitle sync.Map
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				id, err := r.AddTopic(fmt.Sprintf("Topic%d-%d", w, i), sched)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids <- id
+			}
+			// Only one of the workers adds the shared title.
+			if _, err := r.AddTopic("Shared", sched); err == nil {
+				sameTitle.Store(w, true)
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("got id %d twice; want unique ids", id)
+		}
+		seen[id] = true
+	}
+	added := 0
+	sameTitle.Range(func(_, _ any) bool {
+		added++
+		return true
+	})
+	if added != 1 {
+		t.Errorf("got the same title added %d times; want once", added)
+	}
+
+	topics, err := r.GetAllTopics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != workers*perWorker+1 {
+		t.Errorf("got %d topics; want %d", len(topics), workers*perWorker+1)
+	}
+}
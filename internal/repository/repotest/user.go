@@ -0,0 +1,86 @@
+package repotest
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// TestUserRepository checks the contract of repository.UserRepository.
+func TestUserRepository(t *testing.T, newRepo NewUserRepository) {
+	t.Run("AddUserAndGetUser", func(t *testing.T) {
+		r := newRepo(t)
+		err := r.AddUser(&entity.User{Name: "User", PasswdHash: "hash"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := r.GetUser("User")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Name != "User" || u.PasswdHash != "hash" {
+			t.Errorf("got %+v; want User with its hash", u)
+		}
+
+		u, err = r.GetUser("NonExistentUser")
+		wantError[common.UserNotExistError](t, err)
+		if u != nil {
+			t.Error("got non-nil; want nil")
+		}
+	})
+
+	t.Run("AddUserWithEmptyName", func(t *testing.T) {
+		r := newRepo(t)
+		wantError[common.EmptyUserName](t, r.AddUser(&entity.User{}))
+	})
+
+	t.Run("AddUserTwice", func(t *testing.T) {
+		r := newRepo(t)
+		if err := r.AddUser(&entity.User{Name: "User"}); err != nil {
+			t.Fatal(err)
+		}
+		wantError[common.UserAlreadyExistsError](t, r.AddUser(&entity.User{Name: "User"}))
+	})
+
+	t.Run("RemoveUser", func(t *testing.T) {
+		r := newRepo(t)
+		if err := r.AddUser(&entity.User{Name: "User"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.RemoveUser("User"); err != nil {
+			t.Fatal(err)
+		}
+		_, err := r.GetUser("User")
+		wantError[common.UserNotExistError](t, err)
+
+		if err = r.RemoveUser("User"); err != nil {
+			t.Errorf("got %v; want nil", err)
+		}
+		if err = r.AddUser(&entity.User{Name: "User"}); err != nil {
+			t.Errorf("got %v; want the name free again", err)
+		}
+	})
+
+	t.Run("ConcurrentAddUser", func(t *testing.T) {
+		r := newRepo(t)
+		var wg sync.WaitGroup
+		var added atomic.Int32
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if r.AddUser(&entity.User{Name: "User"}) == nil {
+					added.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		if added.Load() != 1 {
+			t.Errorf("got the user added %d times; want once", added.Load())
+		}
+	})
+}
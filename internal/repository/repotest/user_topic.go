@@ -0,0 +1,99 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+)
+
+// TestUserTopicRepository checks that topics of different users
+// are kept apart and that topics survive reopening the storage.
+func TestUserTopicRepository(t *testing.T, open OpenUserTopicRepository) {
+	clk := clock.NewFake(start)
+	repos, closeRepos := open(t, clk)
+
+	r := userTopics(t, repos, "alice")
+	id, err := r.AddTopic("MyTopic", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, err := r.AddTopic("Removed", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.RemoveTopic(removed); err != nil {
+		t.Fatal(err)
+	}
+	want, err := r.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want.State.Level = 3
+	want.State.Interval = 36 * time.Hour
+	want.BuriedUntil = start.Add(time.Hour)
+	if err = r.UpdateTopic(want); err != nil {
+		t.Fatal(err)
+	}
+
+	// Topics of other users are kept apart.
+	other := userTopics(t, repos, "bob")
+	if _, err = other.AddTopic("MyTopic", sched); err != nil {
+		t.Errorf("got %v; want the same title allowed for another user", err)
+	}
+	_, err = r.AddTopic("MyTopic", sched)
+	wantError[common.TopicTitleConflictError](t, err)
+	closeRepos()
+
+	repos, closeRepos = open(t, clk)
+	defer closeRepos()
+	r = userTopics(t, repos, "alice")
+	got, err := r.GetTopicById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != want.Title || got.State.Level != 3 || got.State.Interval != 36*time.Hour {
+		t.Errorf("got %+v; want the stored topic", got)
+	}
+	if !got.Created.Equal(start) || !got.NextRepeat.Equal(want.NextRepeat) ||
+		!got.BuriedUntil.Equal(want.BuriedUntil) {
+		t.Errorf("got created %s, next repeat %s, buried until %s; want %s, %s, %s",
+			got.Created, got.NextRepeat, got.BuriedUntil,
+			start, want.NextRepeat, want.BuriedUntil)
+	}
+	all, err := r.GetAllTopics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Errorf("got %d topics of alice; want 1", len(all))
+	}
+
+	// Ids of removed topics are not reused.
+	next, err := r.AddTopic("Next", sched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != removed+1 {
+		t.Errorf("got id %d; want %d", next, removed+1)
+	}
+
+	all, err = userTopics(t, repos, "bob").GetAllTopics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].Title != "MyTopic" {
+		t.Errorf("got %+v; want the topic of bob", all)
+	}
+}
+
+func userTopics(t *testing.T, repos repo.UserTopicRepository, name string) repo.TopicRepository {
+	t.Helper()
+	r, err := repos.GetUserTopicRepository(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
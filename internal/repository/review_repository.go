@@ -0,0 +1,19 @@
+package repository
+
+import "github.com/Ayaya-zx/mem-flow/internal/entity"
+
+// ReviewRepository stores the history of topic repetitions
+// associated with user names.
+type ReviewRepository interface {
+	// AddReview appends a review event to the history of the user
+	// with the given name.
+	AddReview(name string, e *entity.ReviewEvent) error
+	// GetReviews returns all reviews of the user with the given name
+	// in chronological order.
+	GetReviews(name string) ([]*entity.ReviewEvent, error)
+	// GetTopicReviews returns reviews of the topic with the given id
+	// in chronological order.
+	GetTopicReviews(name string, topicId int) ([]*entity.ReviewEvent, error)
+	// RemoveTopicReviews deletes the history of the topic with the given id.
+	RemoveTopicReviews(name string, topicId int) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/Ayaya-zx/mem-flow/internal/entity"
+
+// SettingsRepository stores settings associated with user names.
+type SettingsRepository interface {
+	// GetSettings returns settings of the user with the given name.
+	// Users who never saved their settings get the default ones.
+	GetSettings(name string) (*entity.Settings, error)
+	// SetSettings replaces settings of the user with the given name.
+	SetSettings(name string, s *entity.Settings) error
+}
@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// migrations are applied in order to bring the schema up to date.
+// The number of applied migrations is kept in PRAGMA user_version.
+// Never change a migration once it is released, append a new one.
+var migrations = []string{
+	`CREATE TABLE users (
+		name        TEXT PRIMARY KEY,
+		passwd_hash BLOB NOT NULL
+	);
+	CREATE TABLE topic_repositories (
+		id            INTEGER PRIMARY KEY AUTOINCREMENT,
+		next_topic_id INTEGER NOT NULL DEFAULT 1
+	);
+	CREATE TABLE user_topic_repositories (
+		name          TEXT PRIMARY KEY,
+		repository_id INTEGER NOT NULL REFERENCES topic_repositories (id)
+	);
+	CREATE TABLE topics (
+		repository_id INTEGER NOT NULL REFERENCES topic_repositories (id),
+		id            INTEGER NOT NULL,
+		title         TEXT NOT NULL,
+		created       INTEGER NOT NULL,
+		last_repeated INTEGER NOT NULL,
+		next_repeat   INTEGER NOT NULL,
+		state         TEXT NOT NULL,
+		suspended     INTEGER NOT NULL DEFAULT 0,
+		buried_until  INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (repository_id, id),
+		UNIQUE (repository_id, title)
+	);
+	CREATE INDEX topics_next_repeat ON topics (repository_id, next_repeat);
+	CREATE TABLE settings (
+		name TEXT PRIMARY KEY,
+		data TEXT NOT NULL
+	);
+	CREATE TABLE reviews (
+		seq           INTEGER PRIMARY KEY AUTOINCREMENT,
+		name          TEXT NOT NULL,
+		topic_id      INTEGER NOT NULL,
+		time          INTEGER NOT NULL,
+		grade         INTEGER NOT NULL,
+		prev_interval INTEGER NOT NULL,
+		new_interval  INTEGER NOT NULL,
+		elapsed       INTEGER NOT NULL
+	);
+	CREATE INDEX reviews_topic ON reviews (name, topic_id);`,
+}
+
+// Open opens the database at the given path, creating it
+// if needed, and applies the missing migrations.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+path+
+		"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows only one writer at a time. A single connection
+	// serializes the transactions instead of failing them as busy.
+	db.SetMaxOpenConns(1)
+
+	if err = migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	err := db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than the supported %d",
+			version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		err = inTx(db, func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migrations[i]); err != nil {
+				return fmt.Errorf("migration %d: %w", i+1, err)
+			}
+			// PRAGMA does not accept parameters.
+			_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inTx runs f in a transaction which is committed
+// if f succeeds and rolled back otherwise.
+func inTx(db *sql.DB, f func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err = f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// isUniqueViolation reports whether err is caused by
+// a UNIQUE or PRIMARY KEY constraint.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE ||
+		sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// Times are stored as microseconds since the Unix epoch, so they
+// keep their order in indexes. The zero time is stored as 0.
+
+func toUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMicro()
+}
+
+func fromUnix(v int64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(v)
+}
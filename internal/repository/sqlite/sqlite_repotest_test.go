@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+	"github.com/Ayaya-zx/mem-flow/internal/repository/repotest"
+)
+
+// openTestDB opens an empty database which is closed
+// at the end of the test.
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := Open(filepath.Join(t.TempDir(), "memflow.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSqliteTopicRepositoryContract(t *testing.T) {
+	repotest.TestTopicRepository(t, func(t *testing.T, clk clock.Clock) repo.TopicRepository {
+		r, err := NewSqliteTopicRepositoryFactory(openTestDB(t), clk).CreateTopicRepository()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	})
+}
+
+func TestSqliteUserRepositoryContract(t *testing.T) {
+	repotest.TestUserRepository(t, func(t *testing.T) repo.UserRepository {
+		return NewSqliteUserRepository(openTestDB(t))
+	})
+}
+
+func TestSqliteSettingsRepositoryContract(t *testing.T) {
+	repotest.TestSettingsRepository(t, func(t *testing.T) repo.SettingsRepository {
+		return NewSqliteSettingsRepository(openTestDB(t))
+	})
+}
+
+func TestSqliteReviewRepositoryContract(t *testing.T) {
+	repotest.TestReviewRepository(t, func(t *testing.T) repo.ReviewRepository {
+		return NewSqliteReviewRepository(openTestDB(t))
+	})
+}
+
+func TestSqliteUserTopicRepositoryContract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memflow.db")
+	repotest.TestUserTopicRepository(t, func(t *testing.T, clk clock.Clock) (repo.UserTopicRepository, func()) {
+		// Opening the database again must not apply the migrations twice.
+		db, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewSqliteUserTopicRepository(db, NewSqliteTopicRepositoryFactory(db, clk)), func() { db.Close() }
+	})
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// SqliteReviewRepository is an SQLite implementation
+// of review repository. It is safe for concurent use
+// by multiple goroutines.
+type SqliteReviewRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteReviewRepository(db *sql.DB) *SqliteReviewRepository {
+	return &SqliteReviewRepository{db: db}
+}
+
+func (r *SqliteReviewRepository) AddReview(name string, e *entity.ReviewEvent) error {
+	_, err := r.db.Exec(
+		`INSERT INTO reviews (name, topic_id, time, grade,
+			prev_interval, new_interval, elapsed)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		name, e.TopicId, toUnix(e.Time), int(e.Grade),
+		int64(e.PrevInterval), int64(e.NewInterval), int64(e.Elapsed),
+	)
+	return err
+}
+
+func (r *SqliteReviewRepository) GetReviews(name string) ([]*entity.ReviewEvent, error) {
+	return r.queryReviews("WHERE name = ?", name)
+}
+
+func (r *SqliteReviewRepository) GetTopicReviews(name string, topicId int) ([]*entity.ReviewEvent, error) {
+	return r.queryReviews("WHERE name = ? AND topic_id = ?", name, topicId)
+}
+
+func (r *SqliteReviewRepository) RemoveTopicReviews(name string, topicId int) error {
+	_, err := r.db.Exec(
+		"DELETE FROM reviews WHERE name = ? AND topic_id = ?",
+		name, topicId,
+	)
+	return err
+}
+
+func (r *SqliteReviewRepository) queryReviews(where string, args ...any) ([]*entity.ReviewEvent, error) {
+	rows, err := r.db.Query(
+		`SELECT topic_id, time, grade, prev_interval, new_interval, elapsed
+		FROM reviews `+where+" ORDER BY seq",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*entity.ReviewEvent, 0)
+	for rows.Next() {
+		e := new(entity.ReviewEvent)
+		var t, prev, next, elapsed int64
+		err = rows.Scan(&e.TopicId, &t, &e.Grade, &prev, &next, &elapsed)
+		if err != nil {
+			return nil, err
+		}
+		e.Time = fromUnix(t)
+		e.PrevInterval = time.Duration(prev)
+		e.NewInterval = time.Duration(next)
+		e.Elapsed = time.Duration(elapsed)
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// SqliteSettingsRepository is an SQLite implementation
+// of settings repository. It is safe for concurent use
+// by multiple goroutines.
+type SqliteSettingsRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteSettingsRepository(db *sql.DB) *SqliteSettingsRepository {
+	return &SqliteSettingsRepository{db: db}
+}
+
+func (r *SqliteSettingsRepository) GetSettings(name string) (*entity.Settings, error) {
+	var data string
+	err := r.db.QueryRow("SELECT data FROM settings WHERE name = ?", name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &entity.Settings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := new(entity.Settings)
+	if err = json.Unmarshal([]byte(data), s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *SqliteSettingsRepository) SetSettings(name string, s *entity.Settings) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`INSERT INTO settings (name, data) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET data = excluded.data`,
+		name, string(data),
+	)
+	return err
+}
@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+const topicColumns = `id, title, created, last_repeated, next_repeat,
+	state, suspended, buried_until`
+
+// SqliteTopicRepository is an SQLite implementation of topics repository.
+// Topics of all repositories are kept in the same table and told apart
+// by the repository id. It is safe for concurent use by multiple goroutines.
+type SqliteTopicRepository struct {
+	db    *sql.DB
+	id    int64
+	clock clock.Clock
+}
+
+func (ts *SqliteTopicRepository) AddTopic(title string, s entity.Scheduler) (int, error) {
+	if title == "" {
+		return 0, common.TopicTitleError("topic's title is empty")
+	}
+
+	var topic *entity.Topic
+	err := inTx(ts.db, func(tx *sql.Tx) error {
+		var id int
+		err := tx.QueryRow(
+			"SELECT next_topic_id FROM topic_repositories WHERE id = ?", ts.id,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		topic = entity.NewTopic(id, title, ts.clock.Now(), s)
+		state, err := json.Marshal(topic.State)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(
+			"INSERT INTO topics (repository_id, "+topicColumns+
+				") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			ts.id, topic.Id, topic.Title,
+			toUnix(topic.Created), toUnix(topic.LastRepeated), toUnix(topic.NextRepeat),
+			string(state), topic.Suspended, toUnix(topic.BuriedUntil),
+		)
+		if isUniqueViolation(err) {
+			return common.TopicTitleConflictError(fmt.Sprintf(
+				"topic %s already exists",
+				title,
+			))
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			"UPDATE topic_repositories SET next_topic_id = ? WHERE id = ?",
+			id+1, ts.id,
+		)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return topic.Id, nil
+}
+
+func (ts *SqliteTopicRepository) RemoveTopic(id int) error {
+	_, err := ts.db.Exec(
+		"DELETE FROM topics WHERE repository_id = ? AND id = ?",
+		ts.id, id,
+	)
+	return err
+}
+
+func (ts *SqliteTopicRepository) GetAllTopics() ([]*entity.Topic, error) {
+	return ts.queryTopics(
+		"SELECT "+topicColumns+" FROM topics WHERE repository_id = ? ORDER BY id",
+		ts.id,
+	)
+}
+
+func (ts *SqliteTopicRepository) GetDueTopics(now time.Time, limit int) ([]*entity.Topic, error) {
+	if limit <= 0 {
+		// SQLite treats a negative limit as no limit.
+		limit = -1
+	}
+	return ts.queryTopics(
+		"SELECT "+topicColumns+` FROM topics
+		WHERE repository_id = ? AND next_repeat <= ?
+			AND suspended = 0 AND buried_until <= ?
+		ORDER BY next_repeat, id LIMIT ?`,
+		ts.id, toUnix(now), toUnix(now), limit,
+	)
+}
+
+func (ts *SqliteTopicRepository) GetTopicById(id int) (*entity.Topic, error) {
+	topics, err := ts.queryTopics(
+		"SELECT "+topicColumns+" FROM topics WHERE repository_id = ? AND id = ?",
+		ts.id, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(topics) == 0 {
+		return nil, common.TopicNotExistsError(
+			fmt.Sprintf("topic with id %d does not exist", id))
+	}
+	return topics[0], nil
+}
+
+func (ts *SqliteTopicRepository) UpdateTopic(t *entity.Topic) error {
+	if t.Title == "" {
+		return common.TopicTitleError("topic's title is empty")
+	}
+	state, err := json.Marshal(t.State)
+	if err != nil {
+		return err
+	}
+
+	res, err := ts.db.Exec(`UPDATE topics SET title = ?, created = ?,
+			last_repeated = ?, next_repeat = ?, state = ?,
+			suspended = ?, buried_until = ?
+		WHERE repository_id = ? AND id = ?`,
+		t.Title, toUnix(t.Created), toUnix(t.LastRepeated), toUnix(t.NextRepeat),
+		string(state), t.Suspended, toUnix(t.BuriedUntil),
+		ts.id, t.Id,
+	)
+	if isUniqueViolation(err) {
+		return common.TopicTitleConflictError(fmt.Sprintf(
+			"topic %s already exists",
+			t.Title,
+		))
+	}
+	return ts.checkUpdated(t.Id, res, err)
+}
+
+func (ts *SqliteTopicRepository) SuspendTopic(id int) error {
+	res, err := ts.db.Exec(
+		"UPDATE topics SET suspended = 1 WHERE repository_id = ? AND id = ?",
+		ts.id, id,
+	)
+	return ts.checkUpdated(id, res, err)
+}
+
+func (ts *SqliteTopicRepository) BuryTopic(id int, until time.Time) error {
+	res, err := ts.db.Exec(
+		"UPDATE topics SET buried_until = ? WHERE repository_id = ? AND id = ?",
+		toUnix(until), ts.id, id,
+	)
+	return ts.checkUpdated(id, res, err)
+}
+
+func (ts *SqliteTopicRepository) UnsuspendTopic(id int) error {
+	res, err := ts.db.Exec(
+		"UPDATE topics SET suspended = 0, buried_until = 0 WHERE repository_id = ? AND id = ?",
+		ts.id, id,
+	)
+	return ts.checkUpdated(id, res, err)
+}
+
+// checkUpdated returns the error of the update of the topic
+// with the given id or an error if there is no such topic.
+func (ts *SqliteTopicRepository) checkUpdated(id int, res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return common.TopicNotExistsError(
+			fmt.Sprintf("topic with id %d does not exist", id))
+	}
+	return nil
+}
+
+func (ts *SqliteTopicRepository) queryTopics(query string, args ...any) ([]*entity.Topic, error) {
+	rows, err := ts.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*entity.Topic, 0)
+	for rows.Next() {
+		t := new(entity.Topic)
+		var created, lastRepeated, nextRepeat, buriedUntil int64
+		var state string
+		err = rows.Scan(&t.Id, &t.Title, &created, &lastRepeated, &nextRepeat,
+			&state, &t.Suspended, &buriedUntil)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(state), &t.State); err != nil {
+			return nil, err
+		}
+		t.Created = fromUnix(created)
+		t.LastRepeated = fromUnix(lastRepeated)
+		t.NextRepeat = fromUnix(nextRepeat)
+		t.BuriedUntil = fromUnix(buriedUntil)
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
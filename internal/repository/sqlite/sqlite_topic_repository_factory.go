@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/Ayaya-zx/mem-flow/internal/clock"
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+)
+
+// SqliteTopicRepositoryFactory creates topic repositories
+// in the same database.
+type SqliteTopicRepositoryFactory struct {
+	db    *sql.DB
+	clock clock.Clock
+}
+
+func NewSqliteTopicRepositoryFactory(db *sql.DB, clk clock.Clock) *SqliteTopicRepositoryFactory {
+	return &SqliteTopicRepositoryFactory{db: db, clock: clk}
+}
+
+func (f *SqliteTopicRepositoryFactory) CreateTopicRepository() (repo.TopicRepository, error) {
+	res, err := f.db.Exec("INSERT INTO topic_repositories DEFAULT VALUES")
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return f.topicRepository(id), nil
+}
+
+// topicRepository returns the existing repository with the given id.
+func (f *SqliteTopicRepositoryFactory) topicRepository(id int64) *SqliteTopicRepository {
+	return &SqliteTopicRepository{db: f.db, id: id, clock: f.clock}
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// SqliteUserRepository is an SQLite implementation of users repository.
+// It is safe for concurent use by multiple goroutines.
+type SqliteUserRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteUserRepository(db *sql.DB) *SqliteUserRepository {
+	return &SqliteUserRepository{db: db}
+}
+
+func (r *SqliteUserRepository) AddUser(u *entity.User) error {
+	if u.Name == "" {
+		return common.EmptyUserName("user name is empty")
+	}
+	_, err := r.db.Exec(
+		"INSERT INTO users (name, passwd_hash) VALUES (?, ?)",
+		// A string would be stored as TEXT even in a BLOB column.
+		u.Name, []byte(u.PasswdHash),
+	)
+	if isUniqueViolation(err) {
+		return common.UserAlreadyExistsError(
+			fmt.Sprintf("user with name %s already exists",
+				u.Name),
+		)
+	}
+	return err
+}
+
+func (r *SqliteUserRepository) GetUser(name string) (*entity.User, error) {
+	var hash []byte
+	err := r.db.QueryRow(
+		"SELECT passwd_hash FROM users WHERE name = ?", name,
+	).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.UserNotExistError(
+			fmt.Sprintf(
+				"user with name %s does not exist", name,
+			),
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entity.User{Name: name, PasswdHash: string(hash)}, nil
+}
+
+func (r *SqliteUserRepository) RemoveUser(name string) error {
+	_, err := r.db.Exec("DELETE FROM users WHERE name = ?", name)
+	return err
+}
@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestPasswordHashIsBlob(t *testing.T) {
+	db := openTestDB(t)
+	r := NewSqliteUserRepository(db)
+	// Hashes are arbitrary bytes, not valid UTF-8.
+	hash := "\xff\x00\xfe hash"
+	if err := r.AddUser(&entity.User{Name: "alice", PasswdHash: hash}); err != nil {
+		t.Fatal(err)
+	}
+
+	var typ string
+	err := db.QueryRow("SELECT typeof(passwd_hash) FROM users WHERE name = ?", "alice").Scan(&typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ != "blob" {
+		t.Errorf("got passwd_hash stored as %s; want blob", typ)
+	}
+
+	u, err := r.GetUser("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.PasswdHash != hash {
+		t.Errorf("got hash %q; want %q", u.PasswdHash, hash)
+	}
+}
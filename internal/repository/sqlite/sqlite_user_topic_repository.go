@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+
+	repo "github.com/Ayaya-zx/mem-flow/internal/repository"
+)
+
+// SqliteUserTopicRepository is an SQLite implementation
+// of user topics repository. It is safe for concurent use
+// by multiple goroutines.
+type SqliteUserTopicRepository struct {
+	m       sync.Mutex
+	db      *sql.DB
+	factory *SqliteTopicRepositoryFactory
+}
+
+func NewSqliteUserTopicRepository(db *sql.DB, factory *SqliteTopicRepositoryFactory) *SqliteUserTopicRepository {
+	return &SqliteUserTopicRepository{db: db, factory: factory}
+}
+
+func (r *SqliteUserTopicRepository) GetUserTopicRepository(name string) (repo.TopicRepository, error) {
+	// The lock keeps two repositories from being
+	// created for the same user at once.
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var id int64
+	err := r.db.QueryRow(
+		"SELECT repository_id FROM user_topic_repositories WHERE name = ?", name,
+	).Scan(&id)
+	if err == nil {
+		return r.factory.topicRepository(id), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	topicRepo, err := r.factory.CreateTopicRepository()
+	if err != nil {
+		return nil, err
+	}
+	_, err = r.db.Exec(
+		"INSERT INTO user_topic_repositories (name, repository_id) VALUES (?, ?)",
+		name, topicRepo.(*SqliteTopicRepository).id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return topicRepo, nil
+}
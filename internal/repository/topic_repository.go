@@ -1,14 +1,32 @@
 package repository
 
-import "github.com/Ayaya-zx/mem-flow/internal/entity"
+import (
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
 
 type TopicRepository interface {
 	// AddTopic adds a topic with a given title to the repository.
-	AddTopic(title string) (int, error)
+	// Its first repetition is chosen by s, nil s makes it due at once.
+	AddTopic(title string, s entity.Scheduler) (int, error)
 	// RemoveTopic deletes a topic from the repository by id.
 	RemoveTopic(id int) error
 	// GetAllTopics returns all topics stored at the repository.
 	GetAllTopics() ([]*entity.Topic, error)
+	// GetDueTopics returns up to limit topics which are due at now
+	// and neither suspended nor buried, the most overdue first.
+	// Non-positive limit means no limit.
+	GetDueTopics(now time.Time, limit int) ([]*entity.Topic, error)
 	// GetTopic returns topic by id.
 	GetTopicById(id int) (*entity.Topic, error)
+	// UpdateTopic replaces the stored topic with the same id.
+	UpdateTopic(t *entity.Topic) error
+	// SuspendTopic excludes a topic from repetitions until
+	// it is unsuspended.
+	SuspendTopic(id int) error
+	// BuryTopic excludes a topic from repetitions until the given time.
+	BuryTopic(id int, until time.Time) error
+	// UnsuspendTopic returns a suspended or buried topic to repetitions.
+	UnsuspendTopic(id int) error
 }
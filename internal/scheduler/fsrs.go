@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+const (
+	fsrsDecay  = -0.5
+	fsrsFactor = 19.0 / 81.0
+
+	fsrsMinDifficulty = 1
+	fsrsMaxDifficulty = 10
+	fsrsMaxInterval   = 36500 * day
+
+	// DefaultRetentionTarget is the probability of recall
+	// FSRS aims for when the user did not choose one.
+	DefaultRetentionTarget = 0.9
+)
+
+// DefaultFSRSWeights are the parameters of FSRS-4.5
+// fitted on a large collection of reviews.
+var DefaultFSRSWeights = []float64{
+	0.4872, 1.4003, 3.7145, 13.8206,
+	5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072,
+	0.0793, 0.3246, 1.587, 0.2272,
+	2.8755,
+}
+
+// fsrsRating is a grade on the four-point FSRS scale.
+type fsrsRating int
+
+const (
+	fsrsAgain fsrsRating = iota + 1
+	fsrsHard
+	fsrsGood
+	fsrsEasy
+)
+
+func toFSRSRating(g entity.Grade) fsrsRating {
+	switch {
+	case !g.Passed():
+		return fsrsAgain
+	case g == entity.GradeHard:
+		return fsrsHard
+	case g == entity.GradeGood:
+		return fsrsGood
+	default:
+		return fsrsEasy
+	}
+}
+
+// FSRS implements the Free Spaced Repetition Scheduler.
+// Every topic has a stability, the number of days after which
+// the probability to recall it drops to 90%, and a difficulty
+// between 1 and 10. The next repetition is scheduled when the
+// probability of recall drops to the retention target.
+type FSRS struct {
+	dueAtOnce
+	Weights         []float64
+	RetentionTarget float64
+}
+
+// NewFSRS returns FSRS scheduler with the given weights and retention
+// target. Empty weights and zero retention target mean the defaults.
+func NewFSRS(weights []float64, retentionTarget float64) (*FSRS, error) {
+	if len(weights) == 0 {
+		weights = DefaultFSRSWeights
+	}
+	if len(weights) != len(DefaultFSRSWeights) {
+		return nil, common.InvalidSettingsError(fmt.Sprintf(
+			"FSRS needs %d weights, got %d",
+			len(DefaultFSRSWeights), len(weights)))
+	}
+	if retentionTarget == 0 {
+		retentionTarget = DefaultRetentionTarget
+	}
+	if retentionTarget < 0.7 || retentionTarget > 0.99 {
+		return nil, common.InvalidSettingsError(fmt.Sprintf(
+			"retention target %g is not between 0.7 and 0.99",
+			retentionTarget))
+	}
+	return &FSRS{
+		Weights:         weights,
+		RetentionTarget: retentionTarget,
+	}, nil
+}
+
+func (f *FSRS) Schedule(t entity.Topic, g entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	state := t.State
+	rating := toFSRSRating(g)
+
+	if state.Stability == 0 {
+		state.Stability = f.initStability(rating)
+		state.Difficulty = f.initDifficulty(rating)
+	} else {
+		elapsed := now.Sub(t.LastRepeated).Hours() / 24
+		r := retrievability(elapsed, state.Stability)
+		// The difficulty was already changed when the topic was
+		// forgotten, so graduating from relearning keeps it.
+		if !state.Relearning {
+			state.Difficulty = f.nextDifficulty(state.Difficulty, rating)
+		}
+		if rating == fsrsAgain {
+			state.Stability = f.forgetStability(state.Difficulty, state.Stability, r)
+		} else {
+			state.Stability = f.recallStability(state.Difficulty, state.Stability, r, rating)
+		}
+	}
+
+	state.Interval = f.interval(state.Stability)
+	return state, now.Add(state.Interval)
+}
+
+// retrievability is the probability to recall a topic
+// with the stability s after elapsed days.
+func retrievability(elapsed, s float64) float64 {
+	return math.Pow(1+fsrsFactor*elapsed/s, fsrsDecay)
+}
+
+func (f *FSRS) interval(s float64) time.Duration {
+	days := s / fsrsFactor * (math.Pow(f.RetentionTarget, 1/fsrsDecay) - 1)
+	days = math.Max(1, math.Round(days))
+	return min(time.Duration(days)*day, fsrsMaxInterval)
+}
+
+func (f *FSRS) initStability(r fsrsRating) float64 {
+	return math.Max(f.Weights[r-1], 0.1)
+}
+
+func (f *FSRS) initDifficulty(r fsrsRating) float64 {
+	return clampDifficulty(f.Weights[4] - float64(r-3)*f.Weights[5])
+}
+
+func (f *FSRS) nextDifficulty(d float64, r fsrsRating) float64 {
+	next := d - f.Weights[6]*float64(r-3)
+	// Mean reversion to the difficulty of a topic first graded as good.
+	next = f.Weights[7]*f.initDifficulty(fsrsGood) + (1-f.Weights[7])*next
+	return clampDifficulty(next)
+}
+
+func (f *FSRS) recallStability(d, s, r float64, rating fsrsRating) float64 {
+	w := f.Weights
+	hardPenalty, easyBonus := 1.0, 1.0
+	if rating == fsrsHard {
+		hardPenalty = w[15]
+	}
+	if rating == fsrsEasy {
+		easyBonus = w[16]
+	}
+	return s * (1 + math.Exp(w[8])*
+		(11-d)*
+		math.Pow(s, -w[9])*
+		(math.Exp(w[10]*(1-r))-1)*
+		hardPenalty*
+		easyBonus)
+}
+
+func (f *FSRS) forgetStability(d, s, r float64) float64 {
+	w := f.Weights
+	next := w[11] *
+		math.Pow(d, -w[12]) *
+		(math.Pow(s+1, w[13]) - 1) *
+		math.Exp(w[14]*(1-r))
+	return math.Min(next, s)
+}
+
+func clampDifficulty(d float64) float64 {
+	return math.Min(math.Max(d, fsrsMinDifficulty), fsrsMaxDifficulty)
+}
@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// FSRSReview is a single repetition of a topic
+// used to fit the FSRS weights.
+type FSRSReview struct {
+	TopicId int          `json:"topicId"`
+	Time    time.Time    `json:"time"`
+	Grade   entity.Grade `json:"grade"`
+	// PrevInterval is the interval the topic was scheduled for
+	// before the repetition. Zero means it is unknown.
+	PrevInterval time.Duration `json:"prevInterval"`
+}
+
+// minOptimizeReviews is the number of predictable repetitions,
+// that is all but the first one of every topic, needed to fit
+// the weights.
+const minOptimizeReviews = 32
+
+// fsrsBounds limit the weights to the ranges
+// where the model stays meaningful.
+var fsrsBounds = [][2]float64{
+	{0.1, 100}, {0.1, 100}, {0.1, 100}, {0.1, 100},
+	{1, 10}, {0.01, 4}, {0.01, 4}, {0, 0.75},
+	{0, 4.5}, {0, 0.8}, {0.01, 3.5}, {0.01, 5},
+	{0.01, 0.25}, {0.01, 0.9}, {0.01, 4}, {0, 1},
+	{1, 6},
+}
+
+// OptimizeFSRS fits the FSRS weights to the review history starting
+// from the weights w, or the default ones if w is empty. It minimizes
+// the log loss of predicted recall probabilities and returns the
+// fitted weights together with the reached loss.
+func OptimizeFSRS(reviews []FSRSReview, w []float64) ([]float64, float64, error) {
+	f, err := NewFSRS(slices.Clone(w), DefaultRetentionTarget)
+	if err != nil {
+		return nil, 0, err
+	}
+	f.Weights = slices.Clone(f.Weights)
+	for i, b := range fsrsBounds {
+		f.Weights[i] = math.Min(math.Max(f.Weights[i], b[0]), b[1])
+	}
+
+	history := groupReviews(longTermReviews(reviews))
+	predictable := 0
+	for _, seq := range history {
+		predictable += len(seq) - 1
+	}
+	if predictable < minOptimizeReviews {
+		return nil, 0, fmt.Errorf(
+			"need at least %d repeated reviews to optimize, got %d",
+			minOptimizeReviews, predictable)
+	}
+
+	// Coordinate-wise pattern search: try to move every weight
+	// in both directions and shrink the steps when nothing helps.
+	steps := make([]float64, len(fsrsBounds))
+	for i, b := range fsrsBounds {
+		steps[i] = (b[1] - b[0]) / 20
+	}
+	best := f.logLoss(history)
+	for round := 0; round < 500; round++ {
+		improved := false
+		for i, b := range fsrsBounds {
+			for _, dir := range []float64{1, -1} {
+				old := f.Weights[i]
+				f.Weights[i] = math.Min(math.Max(old+dir*steps[i], b[0]), b[1])
+				if loss := f.logLoss(history); loss < best {
+					best = loss
+					improved = true
+					break
+				}
+				f.Weights[i] = old
+			}
+		}
+		if improved {
+			continue
+		}
+		done := true
+		for i, b := range fsrsBounds {
+			steps[i] /= 2
+			if steps[i] > (b[1]-b[0])*1e-4 {
+				done = false
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	return f.Weights, best / float64(predictable), nil
+}
+
+// longTermReviews returns the reviews which FSRS models. Reviews
+// of learning and relearning steps are scheduled by Steps rather
+// than by FSRS, so they are left out.
+func longTermReviews(reviews []FSRSReview) []FSRSReview {
+	res := make([]FSRSReview, 0, len(reviews))
+	for _, r := range reviews {
+		if r.PrevInterval == 0 || r.PrevInterval >= day {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// groupReviews splits the reviews by topic and sorts them by time.
+func groupReviews(reviews []FSRSReview) [][]FSRSReview {
+	byTopic := make(map[int][]FSRSReview)
+	for _, r := range reviews {
+		byTopic[r.TopicId] = append(byTopic[r.TopicId], r)
+	}
+	res := make([][]FSRSReview, 0, len(byTopic))
+	for _, seq := range byTopic {
+		slices.SortFunc(seq, func(a, b FSRSReview) int {
+			return a.Time.Compare(b.Time)
+		})
+		res = append(res, seq)
+	}
+	return res
+}
+
+// logLoss replays the history with the current weights and sums
+// the binary cross-entropy of the predicted recall probabilities.
+func (f *FSRS) logLoss(history [][]FSRSReview) float64 {
+	const eps = 1e-6
+	loss := 0.0
+	for _, seq := range history {
+		topic := entity.Topic{}
+		for _, r := range seq {
+			if topic.State.Stability != 0 {
+				elapsed := r.Time.Sub(topic.LastRepeated).Hours() / 24
+				p := retrievability(elapsed, topic.State.Stability)
+				p = math.Min(math.Max(p, eps), 1-eps)
+				if r.Grade.Passed() {
+					loss -= math.Log(p)
+				} else {
+					loss -= math.Log(1 - p)
+				}
+			}
+			topic.State, _ = f.Schedule(topic, r.Grade, r.Time)
+			topic.LastRepeated = r.Time
+		}
+	}
+	return loss
+}
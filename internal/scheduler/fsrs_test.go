@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestFSRSFirstRepetition(t *testing.T) {
+	f, err := NewFSRS(nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	state, next := f.Schedule(entity.Topic{}, entity.GradeGood, now)
+	if state.Stability != DefaultFSRSWeights[2] {
+		t.Errorf("got Stability = %f; want %f", state.Stability, DefaultFSRSWeights[2])
+	}
+	// With the retention target of 0.9 the interval equals the stability.
+	if got := next.Sub(now); got != 4*day {
+		t.Errorf("got interval %s; want 4 days", got)
+	}
+
+	state, next = f.Schedule(entity.Topic{}, entity.GradeBlackout, now)
+	if got := next.Sub(now); got != f.interval(state.Stability) {
+		t.Errorf("got interval %s; want %s", got, f.interval(state.Stability))
+	}
+	if state.Difficulty <= DefaultFSRSWeights[4] {
+		t.Errorf("got Difficulty = %f; want more than %f",
+			state.Difficulty, DefaultFSRSWeights[4])
+	}
+}
+
+func TestFSRSRecallAndLapse(t *testing.T) {
+	f, _ := NewFSRS(nil, 0)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	topic := entity.Topic{LastRepeated: now}
+	topic.State, topic.NextRepeat = f.Schedule(topic, entity.GradeGood, now)
+
+	recalled := topic
+	recalled.State, _ = f.Schedule(topic, entity.GradeGood, topic.NextRepeat)
+	if recalled.State.Stability <= topic.State.Stability {
+		t.Errorf("got Stability = %f after recall; want more than %f",
+			recalled.State.Stability, topic.State.Stability)
+	}
+
+	forgot := topic
+	forgot.State, _ = f.Schedule(topic, entity.GradeWrong, topic.NextRepeat)
+	if forgot.State.Stability >= topic.State.Stability {
+		t.Errorf("got Stability = %f after lapse; want less than %f",
+			forgot.State.Stability, topic.State.Stability)
+	}
+}
+
+func TestFSRSRetentionTarget(t *testing.T) {
+	now := time.Now()
+	topic := entity.Topic{State: entity.ReviewState{Stability: 10, Difficulty: 5}}
+
+	f90, _ := NewFSRS(nil, 0.9)
+	f80, _ := NewFSRS(nil, 0.8)
+	_, next90 := f90.Schedule(topic, entity.GradeGood, now)
+	_, next80 := f80.Schedule(topic, entity.GradeGood, now)
+	if !next80.After(next90) {
+		t.Errorf("got next repetition %s for 0.8; want after %s for 0.9",
+			next80, next90)
+	}
+
+	if _, err := NewFSRS(nil, 1.5); err == nil {
+		t.Errorf("got nil; want error")
+	}
+	if _, err := NewFSRS([]float64{1, 2, 3}, 0); err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
+
+func TestOptimizeFSRS(t *testing.T) {
+	// Simulate a user who forgets faster than the default model predicts.
+	truth, _ := NewFSRS(nil, 0)
+	truth.Weights = append([]float64(nil), DefaultFSRSWeights...)
+	truth.Weights[2] = 1
+	truth.Weights[8] = 1
+
+	rnd := rand.New(rand.NewSource(1))
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var reviews []FSRSReview
+	for id := 1; id <= 40; id++ {
+		topic := entity.Topic{}
+		now := start
+		for i := 0; i < 6; i++ {
+			grade := entity.GradeGood
+			if topic.State.Stability != 0 {
+				elapsed := now.Sub(topic.LastRepeated).Hours() / 24
+				if rnd.Float64() > retrievability(elapsed, topic.State.Stability) {
+					grade = entity.GradeWrong
+				}
+			}
+			reviews = append(reviews, FSRSReview{TopicId: id, Time: now, Grade: grade})
+			topic.State, _ = truth.Schedule(topic, grade, now)
+			topic.LastRepeated = now
+			now = now.Add(time.Duration(1+rnd.Intn(10)) * day)
+		}
+	}
+
+	def, _ := NewFSRS(nil, 0)
+	before := def.logLoss(groupReviews(reviews)) / float64(len(reviews)-40)
+
+	weights, after, err := OptimizeFSRS(reviews, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after >= before {
+		t.Errorf("got loss %f after optimization; want less than %f", after, before)
+	}
+	if len(weights) != len(DefaultFSRSWeights) {
+		t.Errorf("got %d weights; want %d", len(weights), len(DefaultFSRSWeights))
+	}
+
+	if _, _, err = OptimizeFSRS(reviews[:10], nil); err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
+
+func TestOptimizeFSRSSkipsSteps(t *testing.T) {
+	reviews := []FSRSReview{
+		{TopicId: 1, Grade: entity.GradeGood, PrevInterval: 20 * time.Minute},
+		{TopicId: 1, Grade: entity.GradeWrong, PrevInterval: 3 * day},
+		{TopicId: 1, Grade: entity.GradeGood, PrevInterval: 10 * time.Minute},
+		{TopicId: 1, Grade: entity.GradeGood, PrevInterval: day},
+		{TopicId: 2, Grade: entity.GradeGood},
+	}
+	got := longTermReviews(reviews)
+	if len(got) != 3 {
+		t.Fatalf("got %d reviews; want 3", len(got))
+	}
+	for _, r := range got {
+		if r.PrevInterval != 0 && r.PrevInterval < day {
+			t.Errorf("got review of a step after %s; want it skipped", r.PrevInterval)
+		}
+	}
+}
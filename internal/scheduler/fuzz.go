@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// fuzzFactor is the part of an interval by which
+// it can be randomly shortened or lengthened.
+const fuzzFactor = 0.05
+
+// Fuzz randomly changes the intervals chosen by the wrapped
+// scheduler by up to 5% so that topics created or repeated
+// together do not fall due at the same moment.
+type Fuzz struct {
+	entity.Scheduler
+	// Rand is the source of randomness. Nil means the global one.
+	Rand *rand.Rand
+	// Load returns the number of topics due on the day of
+	// the given time. If it is set, the due time is moved to
+	// the least loaded day within the fuzz range.
+	Load func(time.Time) int
+	// Max, if positive, is the longest interval the fuzz
+	// can produce, it should match the MaxInterval setting.
+	Max time.Duration
+}
+
+func (f *Fuzz) Init(now time.Time) (entity.ReviewState, time.Time) {
+	state, next := f.Scheduler.Init(now)
+	return f.fuzz(state, now, next)
+}
+
+func (f *Fuzz) Schedule(t entity.Topic, g entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	state, next := f.Scheduler.Schedule(t, g, now)
+	return f.fuzz(state, now, next)
+}
+
+func (f *Fuzz) fuzz(state entity.ReviewState, now, next time.Time) (entity.ReviewState, time.Time) {
+	interval := next.Sub(now)
+	if interval <= 0 {
+		return state, next
+	}
+
+	delta := time.Duration(float64(interval) * fuzzFactor)
+	lo, hi := interval-delta, interval+delta
+	if f.Max > 0 && hi > f.Max {
+		hi = max(f.Max, lo)
+	}
+	interval = lo + time.Duration(f.float64()*float64(hi-lo))
+	if f.Load != nil {
+		interval = f.balance(now, interval, lo, hi)
+	}
+
+	state.Interval = interval
+	return state, now.Add(interval)
+}
+
+// balance looks for a less loaded day within [lo, hi]
+// keeping the randomly chosen interval if there is none.
+func (f *Fuzz) balance(now time.Time, interval, lo, hi time.Duration) time.Duration {
+	candidates := []time.Duration{lo, hi}
+	for d := interval - day; d >= lo; d -= day {
+		candidates = append(candidates, d)
+	}
+	for d := interval + day; d <= hi; d += day {
+		candidates = append(candidates, d)
+	}
+
+	best, bestLoad := interval, f.Load(now.Add(interval))
+	for _, d := range candidates {
+		if load := f.Load(now.Add(d)); load < bestLoad {
+			best, bestLoad = d, load
+		}
+	}
+	return best
+}
+
+func (f *Fuzz) float64() float64 {
+	if f.Rand == nil {
+		return rand.Float64()
+	}
+	return f.Rand.Float64()
+}
+
+// DueLoad returns a function which tells how many of
+// the topics, not counting suspended ones, are due on
+// the day of the given time.
+func DueLoad(topics []*entity.Topic) func(time.Time) int {
+	counts := make(map[time.Time]int)
+	for _, t := range topics {
+		if !t.Suspended {
+			counts[dayOf(t.NextRepeat)]++
+		}
+	}
+	return func(t time.Time) int {
+		return counts[dayOf(t)]
+	}
+}
+
+// dayOf returns the midnight starting the day of t in local time.
+func dayOf(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestFuzz(t *testing.T) {
+	l, _ := NewLadder(nil)
+	f := &Fuzz{Scheduler: l, Rand: rand.New(rand.NewPCG(1, 2))}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	topic := entity.Topic{State: entity.ReviewState{Level: 4}}
+
+	seen := make(map[time.Time]struct{})
+	for i := 0; i < 20; i++ {
+		state, next := f.Schedule(topic, entity.GradeGood, now)
+		got := next.Sub(now)
+		if got < 30*day*95/100 || got > 30*day*105/100 {
+			t.Errorf("got interval %s; want 30 days ± 5%%", got)
+		}
+		if state.Interval != got {
+			t.Errorf("got Interval = %s; want %s", state.Interval, got)
+		}
+		seen[next] = struct{}{}
+	}
+	if len(seen) < 2 {
+		t.Errorf("got the same due time for all repetitions; want different ones")
+	}
+}
+
+func TestFuzzLoadBalance(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	var topics []*entity.Topic
+	// Every day around the 30th one is busy except the 31st.
+	for d := 27; d <= 33; d++ {
+		if d == 31 {
+			continue
+		}
+		for i := 0; i < 5; i++ {
+			topics = append(topics, &entity.Topic{NextRepeat: now.Add(time.Duration(d) * day)})
+		}
+	}
+
+	l, _ := NewLadder(nil)
+	f := &Fuzz{
+		Scheduler: l,
+		Rand:      rand.New(rand.NewPCG(1, 2)),
+		Load:      DueLoad(topics),
+	}
+	topic := entity.Topic{State: entity.ReviewState{Level: 4}}
+	for i := 0; i < 10; i++ {
+		_, next := f.Schedule(topic, entity.GradeGood, now)
+		if dayOf(next) != dayOf(now.Add(31*day)) {
+			t.Errorf("got due day %s; want %s", dayOf(next), dayOf(now.Add(31*day)))
+		}
+	}
+}
+
+func TestFuzzMax(t *testing.T) {
+	s, err := New(LadderName, &entity.Settings{MaxInterval: entity.Duration(30 * day)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Fuzz{Scheduler: s, Rand: rand.New(rand.NewPCG(1, 2)), Max: 30 * day}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	topic := entity.Topic{State: entity.ReviewState{Level: 6}}
+
+	for i := 0; i < 20; i++ {
+		_, next := f.Schedule(topic, entity.GradeGood, now)
+		if got := next.Sub(now); got < 30*day*95/100 || got > 30*day {
+			t.Errorf("got interval %s; want 30 days - 5%% to 30 days", got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// Ladder moves a topic one step up a fixed list of intervals
+// on every successful repetition. Once the last step is reached
+// the topic stays there. A forgotten topic falls back to the half
+// of its step.
+type Ladder struct {
+	dueAtOnce
+	Intervals []time.Duration
+}
+
+// DefaultLadderIntervals are 8 hours, 24 hours,
+// 2 days, 1 week and 1 month.
+var DefaultLadderIntervals = []time.Duration{
+	8 * time.Hour,
+	24 * time.Hour,
+	2 * day,
+	7 * day,
+	30 * day,
+}
+
+// NewLadder returns a Ladder with the given intervals.
+// Empty intervals mean the default ones.
+func NewLadder(intervals []entity.Duration) (*Ladder, error) {
+	if len(intervals) == 0 {
+		return &Ladder{Intervals: DefaultLadderIntervals}, nil
+	}
+	ds, err := durations("graduating interval", intervals)
+	if err != nil {
+		return nil, err
+	}
+	return &Ladder{Intervals: ds}, nil
+}
+
+func (l *Ladder) Schedule(t entity.Topic, g entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	state := t.State
+	last := entity.Level(len(l.Intervals) - 1)
+	if state.Level > last {
+		state.Level = last
+	}
+	if !g.Passed() {
+		state.Level /= 2
+		state.Interval = l.Intervals[state.Level]
+		return state, now.Add(state.Interval)
+	}
+	state.Interval = l.Intervals[state.Level]
+	next := now.Add(state.Interval)
+	if state.Level < last {
+		state.Level++
+	}
+	return state, next
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestLadderSchedule(t *testing.T) {
+	var tests = []struct {
+		level     entity.Level
+		wantNext  time.Duration
+		wantLevel entity.Level
+	}{
+		{0, 8 * time.Hour, 1},
+		{1, 24 * time.Hour, 2},
+		{2, 2 * 24 * time.Hour, 3},
+		{3, 7 * 24 * time.Hour, 4},
+		{4, 30 * 24 * time.Hour, 4},
+		{10, 30 * 24 * time.Hour, 4},
+	}
+
+	l, err := NewLadder(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		topic := entity.Topic{State: entity.ReviewState{Level: test.level}}
+		state, next := l.Schedule(topic, entity.GradeGood, now)
+		if got := next.Sub(now); got != test.wantNext {
+			t.Errorf("on level %d got interval %s; want %s",
+				test.level, got, test.wantNext)
+		}
+		if state.Level != test.wantLevel {
+			t.Errorf("on level %d got new level %d; want %d",
+				test.level, state.Level, test.wantLevel)
+		}
+	}
+}
+
+func TestNewUnknownScheduler(t *testing.T) {
+	_, err := New("unknown", &entity.Settings{})
+	if err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
+
+func TestLadderForgotten(t *testing.T) {
+	var tests = []struct {
+		level     entity.Level
+		wantLevel entity.Level
+	}{
+		{0, 0},
+		{1, 0},
+		{3, 1},
+		{4, 2},
+	}
+
+	l, _ := NewLadder(nil)
+	now := time.Now()
+	for _, test := range tests {
+		topic := entity.Topic{State: entity.ReviewState{Level: test.level}}
+		state, next := l.Schedule(topic, entity.GradeWrong, now)
+		if state.Level != test.wantLevel {
+			t.Errorf("on level %d got new level %d; want %d",
+				test.level, state.Level, test.wantLevel)
+		}
+		if got := next.Sub(now); got != l.Intervals[test.wantLevel] {
+			t.Errorf("on level %d got interval %s; want %s",
+				test.level, got, l.Intervals[test.wantLevel])
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// Leitner implements the Leitner system. Every topic lives in one
+// of the boxes, and every box has its own review frequency.
+// A remembered topic moves one box up, a forgotten one goes
+// back to the first box.
+type Leitner struct {
+	dueAtOnce
+	// Intervals are the review frequencies of the boxes.
+	Intervals []time.Duration
+}
+
+// DefaultLeitnerIntervals are the review frequencies of five boxes:
+// every day, every 3 days, every week, every 2 weeks and every month.
+var DefaultLeitnerIntervals = []time.Duration{
+	day,
+	3 * day,
+	7 * day,
+	14 * day,
+	30 * day,
+}
+
+// NewLeitner returns Leitner scheduler with one box for each
+// of the given intervals. Empty intervals mean the default ones.
+func NewLeitner(intervals []entity.Duration) (*Leitner, error) {
+	if len(intervals) == 0 {
+		return &Leitner{Intervals: DefaultLeitnerIntervals}, nil
+	}
+	ds, err := durations("box interval", intervals)
+	if err != nil {
+		return nil, err
+	}
+	return &Leitner{Intervals: ds}, nil
+}
+
+func (l *Leitner) Schedule(t entity.Topic, g entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	state := t.State
+	box := min(max(state.Box, 1), len(l.Intervals))
+	if g.Passed() {
+		box = min(box+1, len(l.Intervals))
+	} else {
+		box = 1
+	}
+	state.Box = box
+	state.Interval = l.Intervals[box-1]
+	return state, now.Add(state.Interval)
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestLeitnerSchedule(t *testing.T) {
+	l, err := NewLeitner([]entity.Duration{
+		entity.Duration(day),
+		entity.Duration(2 * day),
+		entity.Duration(4 * day),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		box      int
+		grade    entity.Grade
+		wantBox  int
+		wantNext time.Duration
+	}{
+		{0, entity.GradeGood, 2, 2 * day},
+		{1, entity.GradeGood, 2, 2 * day},
+		{2, entity.GradeEasy, 3, 4 * day},
+		{3, entity.GradeHard, 3, 4 * day},
+		{3, entity.GradeWrong, 1, day},
+		{0, entity.GradeBlackout, 1, day},
+		{7, entity.GradeGood, 3, 4 * day},
+	}
+
+	now := time.Now()
+	for _, test := range tests {
+		topic := entity.Topic{State: entity.ReviewState{Box: test.box}}
+		state, next := l.Schedule(topic, test.grade, now)
+		if state.Box != test.wantBox {
+			t.Errorf("on box %d and grade %d got box %d; want %d",
+				test.box, test.grade, state.Box, test.wantBox)
+		}
+		if got := next.Sub(now); got != test.wantNext {
+			t.Errorf("on box %d and grade %d got interval %s; want %s",
+				test.box, test.grade, got, test.wantNext)
+		}
+	}
+}
+
+func TestNewLeitnerInvalidIntervals(t *testing.T) {
+	_, err := NewLeitner([]entity.Duration{entity.Duration(day), 0})
+	if err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// Overdue adjusts the intervals of the wrapped scheduler to the time
+// actually elapsed since the previous repetition. A topic remembered
+// after its due time has proven to last longer, so a part of the delay
+// is added to its new interval: a quarter for hard recalls, a half
+// for good ones and all of it for easy ones. A topic repeated before
+// its due time gets only a part of the growth of its interval in
+// proportion to the elapsed time. Forgotten topics are not adjusted.
+type Overdue struct {
+	entity.Scheduler
+}
+
+func (o *Overdue) Schedule(t entity.Topic, g entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	state, next := o.Scheduler.Schedule(t, g, now)
+
+	scheduled := t.NextRepeat.Sub(t.LastRepeated)
+	elapsed := now.Sub(t.LastRepeated)
+	if !g.Passed() || scheduled <= 0 || elapsed < 0 {
+		return state, next
+	}
+
+	interval := next.Sub(now)
+	if elapsed > scheduled {
+		delay := elapsed - scheduled
+		switch g {
+		case entity.GradeHard:
+			interval += delay / 4
+		case entity.GradeGood:
+			interval += delay / 2
+		default:
+			interval += delay
+		}
+	} else if interval > scheduled {
+		ratio := float64(elapsed) / float64(scheduled)
+		interval = scheduled + time.Duration(float64(interval-scheduled)*ratio)
+	}
+
+	state.Interval = interval
+	return state, now.Add(interval)
+}
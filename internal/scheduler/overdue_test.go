@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestOverdue(t *testing.T) {
+	s := &Overdue{Scheduler: NewSM2()}
+	last := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Scheduled for 6 days, the next interval is 15 days.
+	topic := entity.Topic{
+		LastRepeated: last,
+		NextRepeat:   last.Add(6 * day),
+		State: entity.ReviewState{
+			EaseFactor:  2.5,
+			Repetitions: 2,
+			Interval:    6 * day,
+		},
+	}
+
+	var tests = []struct {
+		elapsed time.Duration
+		grade   entity.Grade
+		want    time.Duration
+	}{
+		// On time.
+		{6 * day, entity.GradeGood, 15 * day},
+		// 4 days late.
+		{10 * day, entity.GradeHard, 16 * day},
+		{10 * day, entity.GradeGood, 17 * day},
+		{10 * day, entity.GradeEasy, 19 * day},
+		// 3 days early gets a half of the growth.
+		{3 * day, entity.GradeGood, 6*day + 9*day/2},
+		// Forgotten topics are not adjusted.
+		{10 * day, entity.GradeWrong, 0},
+	}
+
+	for _, test := range tests {
+		now := last.Add(test.elapsed)
+		state, next := s.Schedule(topic, test.grade, now)
+		if got := next.Sub(now); got != test.want {
+			t.Errorf("after %s with grade %d got interval %s; want %s",
+				test.elapsed, test.grade, got, test.want)
+		}
+		if test.grade.Passed() && state.Interval != test.want {
+			t.Errorf("after %s with grade %d got Interval = %s; want %s",
+				test.elapsed, test.grade, state.Interval, test.want)
+		}
+	}
+}
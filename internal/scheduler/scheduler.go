@@ -0,0 +1,126 @@
+// Package scheduler contains implementations of entity.Scheduler.
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+const (
+	LadderName  = "ladder"
+	SM2Name     = "sm2"
+	FSRSName    = "fsrs"
+	LeitnerName = "leitner"
+
+	// DefaultName is the name of the scheduler used
+	// when nothing else is configured.
+	DefaultName = LadderName
+)
+
+// DefaultLearningSteps are the intervals a new topic
+// goes through before it is handed to the scheduler.
+var DefaultLearningSteps = []time.Duration{20 * time.Minute}
+
+// DefaultRelearningSteps are the intervals a forgotten topic
+// goes through before it is handed back to the scheduler.
+var DefaultRelearningSteps = []time.Duration{10 * time.Minute}
+
+// New returns the scheduler with the given name
+// configured according to the user settings.
+func New(name string, settings *entity.Settings) (entity.Scheduler, error) {
+	var err error
+	var s entity.Scheduler
+
+	switch name {
+	case LadderName:
+		s, err = NewLadder(settings.GraduatingIntervals)
+	case SM2Name:
+		s = NewSM2()
+	case FSRSName:
+		s, err = NewFSRS(settings.FSRSWeights, settings.RetentionTarget)
+	case LeitnerName:
+		s, err = NewLeitner(settings.LeitnerIntervals)
+	default:
+		return nil, common.UnknownSchedulerError(
+			fmt.Sprintf("unknown scheduler %q", name))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// FSRS takes the elapsed time into account by itself.
+	if name != FSRSName {
+		s = &Overdue{Scheduler: s}
+	}
+
+	if settings.MaxInterval < 0 {
+		return nil, common.InvalidSettingsError("max interval is negative")
+	}
+	if settings.MaxInterval > 0 {
+		s = &MaxInterval{Scheduler: s, Max: time.Duration(settings.MaxInterval)}
+	}
+
+	steps := &Steps{
+		Scheduler:  s,
+		Learning:   DefaultLearningSteps,
+		Relearning: DefaultRelearningSteps,
+	}
+	if len(settings.LearningSteps) > 0 {
+		steps.Learning, err = durations("learning step", settings.LearningSteps)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(settings.RelearningSteps) > 0 {
+		steps.Relearning, err = durations("relearning step", settings.RelearningSteps)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// The first box is where forgotten topics are relearned.
+	if name == LeitnerName {
+		steps.Relearning = nil
+	}
+	return steps, nil
+}
+
+// durations converts the durations from user settings
+// making sure all of them are positive.
+func durations(what string, ds []entity.Duration) ([]time.Duration, error) {
+	res := make([]time.Duration, len(ds))
+	for i, d := range ds {
+		if d <= 0 {
+			return nil, common.InvalidSettingsError(fmt.Sprintf(
+				"%s %d is not positive", what, i+1))
+		}
+		res[i] = time.Duration(d)
+	}
+	return res, nil
+}
+
+// dueAtOnce provides Init for the schedulers which
+// have nothing to prepare for new topics.
+type dueAtOnce struct{}
+
+// Init makes a new topic due right after its creation.
+func (dueAtOnce) Init(now time.Time) (entity.ReviewState, time.Time) {
+	return entity.ReviewState{Version: entity.ReviewStateVersion}, now
+}
+
+// MaxInterval limits the intervals chosen by the wrapped scheduler.
+type MaxInterval struct {
+	entity.Scheduler
+	Max time.Duration
+}
+
+func (m *MaxInterval) Schedule(t entity.Topic, g entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	state, next := m.Scheduler.Schedule(t, g, now)
+	if next.Sub(now) > m.Max {
+		state.Interval = m.Max
+		next = now.Add(m.Max)
+	}
+	return state, next
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+const (
+	sm2InitialEase = 2.5
+	sm2MinEase     = 1.3
+	day            = 24 * time.Hour
+)
+
+// SM2 implements the SuperMemo-2 algorithm. The interval grows
+// by the ease factor of the topic which is adjusted on every
+// successful repetition according to its grade. A failed recall
+// resets the repetitions and makes the topic due at once, Steps
+// decides when it is actually shown again.
+type SM2 struct {
+	dueAtOnce
+}
+
+func NewSM2() *SM2 {
+	return &SM2{}
+}
+
+func (s *SM2) Schedule(t entity.Topic, g entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	state := t.State
+	if state.EaseFactor == 0 {
+		state.EaseFactor = sm2InitialEase
+	}
+
+	if !g.Passed() {
+		state.Repetitions = 0
+		state.Interval = 0
+		return state, now
+	}
+
+	switch state.Repetitions {
+	case 0:
+		state.Interval = day
+	case 1:
+		state.Interval = 6 * day
+	default:
+		days := math.Round(state.Interval.Hours() / 24 * state.EaseFactor)
+		state.Interval = time.Duration(days) * day
+	}
+	state.Repetitions++
+
+	q := float64(entity.GradeEasy - g)
+	state.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if state.EaseFactor < sm2MinEase {
+		state.EaseFactor = sm2MinEase
+	}
+
+	return state, now.Add(state.Interval)
+}
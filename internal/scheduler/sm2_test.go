@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestSM2Intervals(t *testing.T) {
+	s := NewSM2()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	topic := entity.Topic{}
+
+	wantDays := []int{1, 6, 15, 38}
+	for i, want := range wantDays {
+		var next time.Time
+		topic.State, next = s.Schedule(topic, entity.GradeGood, now)
+		if got := next.Sub(now); got != time.Duration(want)*day {
+			t.Errorf("on repetition %d got interval %s; want %d days", i+1, got, want)
+		}
+		if topic.State.Repetitions != i+1 {
+			t.Errorf("got Repetitions = %d; want %d", topic.State.Repetitions, i+1)
+		}
+		now = next
+	}
+	if topic.State.EaseFactor != sm2InitialEase {
+		t.Errorf("got EaseFactor = %f; want %f", topic.State.EaseFactor, sm2InitialEase)
+	}
+}
+
+func TestSM2EaseFactor(t *testing.T) {
+	var tests = []struct {
+		grade entity.Grade
+		want  float64
+	}{
+		{entity.GradeEasy, 2.6},
+		{entity.GradeGood, 2.5},
+		{entity.GradeHard, 2.36},
+	}
+
+	s := NewSM2()
+	now := time.Now()
+	for _, test := range tests {
+		state, _ := s.Schedule(entity.Topic{}, test.grade, now)
+		if diff := state.EaseFactor - test.want; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("on grade %d got EaseFactor = %f; want %f",
+				test.grade, state.EaseFactor, test.want)
+		}
+	}
+
+	topic := entity.Topic{State: entity.ReviewState{EaseFactor: sm2MinEase}}
+	state, _ := s.Schedule(topic, entity.GradeHard, now)
+	if state.EaseFactor != sm2MinEase {
+		t.Errorf("got EaseFactor = %f; want %f", state.EaseFactor, sm2MinEase)
+	}
+}
+
+func TestSM2FailedRecall(t *testing.T) {
+	s := NewSM2()
+	now := time.Now()
+	topic := entity.Topic{State: entity.ReviewState{
+		EaseFactor:  2.2,
+		Repetitions: 5,
+		Interval:    40 * day,
+	}}
+
+	state, next := s.Schedule(topic, entity.GradeWrong, now)
+	if state.Repetitions != 0 {
+		t.Errorf("got Repetitions = %d; want 0", state.Repetitions)
+	}
+	if !next.Equal(now) {
+		t.Errorf("got interval %s; want 0", next.Sub(now))
+	}
+	if state.EaseFactor != 2.2 {
+		t.Errorf("got EaseFactor = %f; want 2.2", state.EaseFactor)
+	}
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// Steps makes new topics go through a list of short learning
+// intervals before the wrapped scheduler takes them over.
+// A forgotten topic counts as a lapse and goes through the
+// relearning intervals before it returns to the wrapped
+// scheduler, which decides how much its interval is reduced.
+// A topic forgotten during the steps starts them anew.
+type Steps struct {
+	entity.Scheduler
+	Learning   []time.Duration
+	Relearning []time.Duration
+}
+
+func (s *Steps) Init(now time.Time) (entity.ReviewState, time.Time) {
+	state, next := s.Scheduler.Init(now)
+	if len(s.Learning) == 0 {
+		return state, next
+	}
+	state.Step = 1
+	state.Interval = s.Learning[0]
+	return state, now.Add(state.Interval)
+}
+
+func (s *Steps) Schedule(t entity.Topic, g entity.Grade, now time.Time) (entity.ReviewState, time.Time) {
+	if t.State.Step == 0 {
+		state, next := s.Scheduler.Schedule(t, g, now)
+		if g.Passed() {
+			return state, next
+		}
+		state.Lapses++
+		if len(s.Relearning) == 0 {
+			return state, next
+		}
+		state.Relearning = true
+		state.Step = 1
+		state.Interval = s.Relearning[0]
+		return state, now.Add(state.Interval)
+	}
+
+	state := t.State
+	steps := s.Learning
+	if state.Relearning {
+		steps = s.Relearning
+	}
+
+	switch {
+	case len(steps) > 0 && !g.Passed():
+		state.Step = 1
+	case state.Step < len(steps):
+		state.Step++
+	default:
+		// Graduate from the steps. The wrapped scheduler
+		// sees whether the topic graduates from relearning.
+		t.State.Step = 0
+		state, next := s.Scheduler.Schedule(t, g, now)
+		state.Relearning = false
+		return state, next
+	}
+	state.Interval = steps[state.Step-1]
+	return state, now.Add(state.Interval)
+}
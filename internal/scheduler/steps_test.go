@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func TestLearningSteps(t *testing.T) {
+	s, err := New(LadderName, &entity.Settings{
+		LearningSteps: []entity.Duration{
+			entity.Duration(10 * time.Minute),
+			entity.Duration(time.Hour),
+		},
+		GraduatingIntervals: []entity.Duration{
+			entity.Duration(day),
+			entity.Duration(3 * day),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	topic := entity.Topic{}
+	var next time.Time
+
+	topic.State, next = s.Init(now)
+	if got := next.Sub(now); got != 10*time.Minute {
+		t.Errorf("got first interval %s; want 10m", got)
+	}
+
+	var tests = []struct {
+		grade entity.Grade
+		want  time.Duration
+	}{
+		{entity.GradeGood, time.Hour},
+		{entity.GradeWrong, 10 * time.Minute},
+		{entity.GradeGood, time.Hour},
+		{entity.GradeGood, day},
+		{entity.GradeGood, 3 * day},
+	}
+	for i, test := range tests {
+		topic.State, next = s.Schedule(topic, test.grade, now)
+		if got := next.Sub(now); got != test.want {
+			t.Errorf("on repetition %d got interval %s; want %s", i+1, got, test.want)
+		}
+	}
+	if topic.State.Step != 0 {
+		t.Errorf("got Step = %d; want 0", topic.State.Step)
+	}
+}
+
+func TestMaxInterval(t *testing.T) {
+	s, err := New(LadderName, &entity.Settings{
+		MaxInterval: entity.Duration(3 * day),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	topic := entity.Topic{State: entity.ReviewState{Level: 4}}
+	state, next := s.Schedule(topic, entity.GradeGood, now)
+	if got := next.Sub(now); got != 3*day {
+		t.Errorf("got interval %s; want 3 days", got)
+	}
+	if state.Interval != 3*day {
+		t.Errorf("got Interval = %s; want 3 days", state.Interval)
+	}
+
+	_, err = New(LadderName, &entity.Settings{MaxInterval: -1})
+	if err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
+
+func TestRelearningSteps(t *testing.T) {
+	s, err := New(LadderName, &entity.Settings{
+		RelearningSteps: []entity.Duration{
+			entity.Duration(10 * time.Minute),
+			entity.Duration(time.Hour),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	topic := entity.Topic{State: entity.ReviewState{Level: 4, Interval: 30 * day}}
+	var next time.Time
+
+	var tests = []struct {
+		grade      entity.Grade
+		want       time.Duration
+		relearning bool
+	}{
+		{entity.GradeBlackout, 10 * time.Minute, true},
+		{entity.GradeGood, time.Hour, true},
+		{entity.GradeWrong, 10 * time.Minute, true},
+		{entity.GradeGood, time.Hour, true},
+		// Graduates at the reduced interval.
+		{entity.GradeGood, 2 * day, false},
+	}
+	for i, test := range tests {
+		topic.State, next = s.Schedule(topic, test.grade, now)
+		if got := next.Sub(now); got != test.want {
+			t.Errorf("on repetition %d got interval %s; want %s", i+1, got, test.want)
+		}
+		if topic.State.Relearning != test.relearning {
+			t.Errorf("on repetition %d got Relearning = %t; want %t",
+				i+1, topic.State.Relearning, test.relearning)
+		}
+	}
+	// Forgetting during relearning is not another lapse.
+	if topic.State.Lapses != 1 {
+		t.Errorf("got Lapses = %d; want 1", topic.State.Lapses)
+	}
+}
+
+func TestLeitnerLapses(t *testing.T) {
+	s, err := New(LeitnerName, &entity.Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	topic := entity.Topic{State: entity.ReviewState{Box: 4}}
+	state, next := s.Schedule(topic, entity.GradeWrong, now)
+	if state.Lapses != 1 {
+		t.Errorf("got Lapses = %d; want 1", state.Lapses)
+	}
+	if state.Relearning {
+		t.Errorf("got Relearning = true; want false")
+	}
+	if got := next.Sub(now); got != DefaultLeitnerIntervals[0] {
+		t.Errorf("got interval %s; want %s", got, DefaultLeitnerIntervals[0])
+	}
+}
+
+func TestFSRSRelearningKeepsDifficulty(t *testing.T) {
+	s, err := New(FSRSName, &entity.Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	topic := entity.Topic{}
+	topic.State, _ = s.Init(now)
+	topic.State, _ = s.Schedule(topic, entity.GradeGood, now)
+	for topic.State.Step != 0 {
+		topic.State, _ = s.Schedule(topic, entity.GradeGood, now)
+	}
+
+	topic.State, _ = s.Schedule(topic, entity.GradeWrong, now)
+	if !topic.State.Relearning {
+		t.Fatal("got Relearning = false; want true")
+	}
+	difficulty := topic.State.Difficulty
+	for topic.State.Relearning {
+		topic.State, _ = s.Schedule(topic, entity.GradeGood, now)
+	}
+	if topic.State.Difficulty != difficulty {
+		t.Errorf("got Difficulty = %g; want %g", topic.State.Difficulty, difficulty)
+	}
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/common"
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// DefaultSpreadDays is the number of days topics due during
+// a vacation are spread over when the user did not choose it.
+const DefaultSpreadDays = 7
+
+// CheckVacation validates the vacation and fills in the defaults.
+func CheckVacation(v *entity.Vacation) error {
+	if !v.End.After(v.Start) {
+		return common.InvalidSettingsError("vacation has to end after it starts")
+	}
+	switch v.Mode {
+	case "":
+		v.Mode = entity.VacationShift
+	case entity.VacationShift:
+	case entity.VacationSpread:
+		if v.SpreadDays < 0 {
+			return common.InvalidSettingsError("vacation spread days are negative")
+		}
+		if v.SpreadDays == 0 {
+			v.SpreadDays = DefaultSpreadDays
+		}
+	default:
+		return common.InvalidSettingsError("unknown vacation mode " + v.Mode)
+	}
+	return nil
+}
+
+// ApplyVacation moves the due times of the topics which fell due
+// during the vacation which actually ended at end. It returns the
+// changed topics. Suspended topics are not changed.
+func ApplyVacation(topics []*entity.Topic, v *entity.Vacation, end time.Time) []*entity.Topic {
+	var affected []*entity.Topic
+	for _, t := range topics {
+		if t.Suspended || t.NextRepeat.Before(v.Start) || !t.NextRepeat.Before(end) {
+			continue
+		}
+		affected = append(affected, t)
+	}
+
+	if v.Mode != entity.VacationSpread {
+		length := end.Sub(v.Start)
+		for _, t := range affected {
+			t.NextRepeat = t.NextRepeat.Add(length)
+		}
+		return affected
+	}
+
+	// Keep the order in which the topics fell due.
+	slices.SortFunc(affected, func(a, b *entity.Topic) int {
+		return a.NextRepeat.Compare(b.NextRepeat)
+	})
+	period := time.Duration(v.SpreadDays) * day
+	for i, t := range affected {
+		t.NextRepeat = end.Add(period * time.Duration(i) / time.Duration(len(affected)))
+	}
+	return affected
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+func vacationTopics(start time.Time) []*entity.Topic {
+	return []*entity.Topic{
+		{Id: 1, NextRepeat: start.Add(-day)},
+		{Id: 2, NextRepeat: start.Add(2 * day)},
+		{Id: 3, NextRepeat: start},
+		{Id: 4, NextRepeat: start.Add(day), Suspended: true},
+		{Id: 5, NextRepeat: start.Add(20 * day)},
+	}
+}
+
+func TestApplyVacationShift(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	v := &entity.Vacation{Start: start, End: start.Add(10 * day)}
+	if err := CheckVacation(v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Mode != entity.VacationShift {
+		t.Fatalf("got Mode = %q; want %q", v.Mode, entity.VacationShift)
+	}
+
+	topics := vacationTopics(start)
+	changed := ApplyVacation(topics, v, start.Add(7*day))
+	if len(changed) != 2 {
+		t.Fatalf("got %d changed topics; want 2", len(changed))
+	}
+	// Topics due after the vacation keep their due times.
+	want := []time.Duration{-day, 9 * day, 7 * day, day, 20 * day}
+	for i, topic := range topics {
+		if got := topic.NextRepeat.Sub(start); got != want[i] {
+			t.Errorf("topic %d: got due in %s; want %s", topic.Id, got, want[i])
+		}
+	}
+}
+
+func TestApplyVacationSpread(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * day)
+	v := &entity.Vacation{Start: start, End: end, Mode: entity.VacationSpread, SpreadDays: 4}
+	if err := CheckVacation(v); err != nil {
+		t.Fatal(err)
+	}
+
+	topics := vacationTopics(start)
+	changed := ApplyVacation(topics, v, end)
+	if len(changed) != 2 {
+		t.Fatalf("got %d changed topics; want 2", len(changed))
+	}
+	// Topic 3 fell due first, so it stays first.
+	want := []time.Duration{-day, 12 * day, 10 * day, day, 20 * day}
+	for i, topic := range topics {
+		if got := topic.NextRepeat.Sub(start); got != want[i] {
+			t.Errorf("topic %d: got due in %s; want %s", topic.Id, got, want[i])
+		}
+	}
+}
+
+func TestCheckVacation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, v := range []entity.Vacation{
+		{Start: start, End: start},
+		{Start: start, End: start.Add(day), Mode: "sleep"},
+		{Start: start, End: start.Add(day), Mode: entity.VacationSpread, SpreadDays: -1},
+	} {
+		if err := CheckVacation(&v); err == nil {
+			t.Errorf("CheckVacation(%+v) succeeded; want error", v)
+		}
+	}
+
+	v := &entity.Vacation{Start: start, End: start.Add(day), Mode: entity.VacationSpread}
+	if err := CheckVacation(v); err != nil {
+		t.Fatal(err)
+	}
+	if v.SpreadDays != DefaultSpreadDays {
+		t.Errorf("got SpreadDays = %d; want %d", v.SpreadDays, DefaultSpreadDays)
+	}
+}
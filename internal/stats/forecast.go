@@ -0,0 +1,77 @@
+// Package stats computes statistics of user topics.
+package stats
+
+import (
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+)
+
+// maxSimulatedRepetitions stops the simulation of a topic
+// whose scheduler does not move it forward.
+const maxSimulatedRepetitions = 1000
+
+// ForecastDay is the number of topics due on a day.
+type ForecastDay struct {
+	Date  time.Time
+	Count int
+}
+
+// Forecast returns how many topics will be due on each of the
+// given number of days starting from the day of now. Topics are
+// assumed to be repeated with a good grade when they are due,
+// and overdue topics are counted on the first day. Suspended
+// topics are skipped and buried ones wait until they are dug out.
+func Forecast(topics []*entity.Topic, s entity.Scheduler, now time.Time, days int) []ForecastDay {
+	res := make([]ForecastDay, days)
+	start := dayOf(now)
+	for i := range res {
+		res[i].Date = start.AddDate(0, 0, i)
+	}
+	if days == 0 {
+		return res
+	}
+	end := res[days-1].Date.AddDate(0, 0, 1)
+
+	for _, topic := range topics {
+		if topic.Suspended {
+			continue
+		}
+		t := *topic
+		if t.NextRepeat.Before(t.BuriedUntil) {
+			t.NextRepeat = t.BuriedUntil
+		}
+		for i := 0; i < maxSimulatedRepetitions && t.NextRepeat.Before(end); i++ {
+			repeated := t.NextRepeat
+			if repeated.Before(now) {
+				repeated = now
+			}
+			res[daysBetween(start, dayOf(repeated))].Count++
+
+			var next time.Time
+			t.State, next = s.Schedule(t, entity.GradeGood, repeated)
+			t.LastRepeated = repeated
+			if !next.After(repeated) {
+				break
+			}
+			t.NextRepeat = next
+		}
+	}
+	return res
+}
+
+// dayOf returns the midnight starting the day of t in local time.
+func dayOf(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// daysBetween returns the number of calendar days from a to b
+// which both have to be midnights.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	au := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	bu := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(bu.Sub(au) / (24 * time.Hour))
+}
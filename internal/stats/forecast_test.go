@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayaya-zx/mem-flow/internal/entity"
+	"github.com/Ayaya-zx/mem-flow/internal/scheduler"
+)
+
+func TestForecast(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	s, err := scheduler.NewLeitner([]entity.Duration{
+		entity.Duration(24 * time.Hour),
+		entity.Duration(2 * 24 * time.Hour),
+		entity.Duration(4 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topics := []*entity.Topic{
+		// Overdue, counted today and then on days 2, 6 and 10.
+		{Id: 1, NextRepeat: now.Add(-48 * time.Hour), State: entity.ReviewState{Box: 1}},
+		// Due on day 3, then on day 7.
+		{Id: 2, NextRepeat: now.Add(3 * 24 * time.Hour), State: entity.ReviewState{Box: 3}},
+		// Beyond the forecast.
+		{Id: 3, NextRepeat: now.Add(20 * 24 * time.Hour)},
+		// Suspended.
+		{Id: 4, NextRepeat: now, Suspended: true},
+		// Buried until day 4, then on day 6.
+		{Id: 5, NextRepeat: now, BuriedUntil: now.Add(4 * 24 * time.Hour)},
+	}
+
+	days := Forecast(topics, s, now, 8)
+	want := []int{1, 0, 1, 1, 1, 0, 2, 1}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days; want %d", len(days), len(want))
+	}
+	for i, d := range days {
+		if d.Count != want[i] {
+			t.Errorf("on day %d got %d topics; want %d", i, d.Count, want[i])
+		}
+		if wantDate := time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.Local); !d.Date.Equal(wantDate) {
+			t.Errorf("on day %d got date %s; want %s", i, d.Date, wantDate)
+		}
+	}
+
+	// The originals are not changed by the simulation.
+	if topics[0].State.Box != 1 {
+		t.Errorf("got topics[0].State.Box = %d; want 1", topics[0].State.Box)
+	}
+}
@@ -0,0 +1,276 @@
+// Package client is a Go client of the mem-flow server API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default retry policy of the client.
+const (
+	DefaultRetries    = 2
+	DefaultMinBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Client calls the mem-flow server API.
+// It is safe for concurrent use by multiple goroutines.
+type Client struct {
+	serverURL  string
+	httpClient *http.Client
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	m     sync.Mutex
+	token string
+	// name and password are used to obtain a new token
+	// when there is none or the old one has expired.
+	name     string
+	password string
+}
+
+// Option configures the client.
+type Option func(c *Client)
+
+// WithHTTPClient makes the client send requests with hc
+// instead of http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRetries sets how many times a failed request is retried.
+// The delay before the first retry is minBackoff, and every next
+// one is twice longer up to maxBackoff. Zero retries turns them off.
+// Non-positive minBackoff means DefaultMinBackoff, and maxBackoff
+// shorter than minBackoff means minBackoff.
+func WithRetries(retries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithToken makes the client use a token obtained earlier.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithCredentials makes the client authenticate with the given
+// name and password when it needs a token.
+func WithCredentials(name, password string) Option {
+	return func(c *Client) {
+		c.name = name
+		c.password = password
+	}
+}
+
+// New returns a client of the server with the given URL,
+// e.g. "http://localhost:8765".
+func New(serverURL string, opts ...Option) *Client {
+	c := &Client{
+		serverURL:  strings.TrimSuffix(serverURL, "/"),
+		httpClient: http.DefaultClient,
+		retries:    DefaultRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.retries = max(c.retries, 0)
+	if c.minBackoff <= 0 {
+		c.minBackoff = DefaultMinBackoff
+	}
+	c.maxBackoff = max(c.maxBackoff, c.minBackoff)
+	return c
+}
+
+// Token returns the current token, so it can be saved
+// and passed to WithToken later.
+func (c *Client) Token() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.token
+}
+
+// Register creates a user and authenticates the client as the user.
+func (c *Client) Register(ctx context.Context, name, password string) error {
+	return c.getToken(ctx, "/registration", name, password)
+}
+
+// Auth authenticates the client. The name and the password are
+// remembered to authenticate again when the token expires.
+func (c *Client) Auth(ctx context.Context, name, password string) error {
+	return c.getToken(ctx, "/auth", name, password)
+}
+
+func (c *Client) getToken(ctx context.Context, path, name, password string) error {
+	data, err := c.send(ctx, http.MethodPost, path, authRequest{Name: name, Password: password}, false)
+	if err != nil {
+		return err
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.token = string(data)
+	c.name = name
+	c.password = password
+	return nil
+}
+
+// reauth obtains a new token with the remembered credentials.
+func (c *Client) reauth(ctx context.Context) error {
+	c.m.Lock()
+	name, password := c.name, c.password
+	c.m.Unlock()
+	if name == "" {
+		return ErrNotAuthenticated
+	}
+	return c.Auth(ctx, name, password)
+}
+
+// call sends an authorized request with the body encoded as JSON
+// and decodes the response into result unless it is nil.
+// If the token has expired, the client authenticates once again.
+func (c *Client) call(ctx context.Context, method, path string, body, result any) error {
+	if c.Token() == "" {
+		if err := c.reauth(ctx); err != nil {
+			return err
+		}
+	}
+
+	data, err := c.send(ctx, method, path, body, true)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		if err = c.reauth(ctx); err != nil {
+			return err
+		}
+		data, err = c.send(ctx, method, path, body, true)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// send sends the request retrying it on temporary failures
+// and returns the body of the response.
+func (c *Client) send(ctx context.Context, method, path string, body any, authorized bool) ([]byte, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	backoff := c.minBackoff
+	for attempt := 0; ; attempt++ {
+		data, err := c.sendOnce(ctx, method, path, payload, authorized)
+		if err == nil || attempt >= c.retries || !retryable(method, err) {
+			return data, err
+		}
+
+		// Random part of the delay keeps clients which failed
+		// at the same time from retrying at the same time.
+		delay := backoff/2 + rand.N(backoff/2+1)
+		backoff = min(2*backoff, c.maxBackoff)
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (c *Client) sendOnce(ctx context.Context, method, path string, payload []byte, authorized bool) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.serverURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorized {
+		req.Header.Set("Authorization", "Bearer "+c.Token())
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, newAPIError(resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+// retryable reports whether the request which failed with err may
+// be sent again. Requests which change data are only retried when
+// the server surely has not processed them.
+func retryable(method string, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		// A network error.
+		return idempotent(method)
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(method)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+type authRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"passwd"`
+}
+
+func topicPath(id int, suffix string) string {
+	return fmt.Sprintf("/topics/%d%s", id, suffix)
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, h http.HandlerFunc, opts ...Option) *Client {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	opts = append([]Option{
+		WithHTTPClient(srv.Client()),
+		WithToken("token"),
+		WithRetries(2, time.Millisecond, time.Millisecond),
+	}, opts...)
+	return New(srv.URL, opts...)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	w.Write([]byte(`{"code":"` + code + `","message":"oops"}`))
+}
+
+func TestTypedErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			// An unknown user is reported as a failed
+			// login, not as a server failure.
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		case "/registration":
+			writeError(w, http.StatusBadRequest, "bad_request")
+			return
+		}
+		if r.Method == http.MethodPost {
+			writeError(w, http.StatusConflict, "title_conflict")
+			return
+		}
+		writeError(w, http.StatusNotFound, "topic_not_found")
+	})
+
+	_, err := c.GetTopicById(context.Background(), 1)
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("got %v; want ErrTopicNotFound", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "oops" {
+		t.Errorf("got %#v; want APIError with the message", err)
+	}
+
+	_, err = c.AddTopic(context.Background(), "MyTopic")
+	if !errors.Is(err, ErrTitleConflict) {
+		t.Errorf("got %v; want ErrTitleConflict", err)
+	}
+
+	err = c.Auth(context.Background(), "nobody", "passwd")
+	if !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("got %v; want ErrNotAuthenticated", err)
+	}
+
+	err = c.Register(context.Background(), "", "passwd")
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("got %v; want ErrBadRequest", err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			writeError(w, http.StatusInternalServerError, "internal")
+			return
+		}
+		w.Write([]byte(`[{"id":1,"title":"MyTopic"}]`))
+	})
+
+	topics, err := c.GetAllTopics(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Title != "MyTopic" {
+		t.Errorf("got %+v; want MyTopic", topics)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("got %d calls; want 3", calls.Load())
+	}
+
+	// Adding a topic might have succeeded, so it is not retried.
+	calls.Store(0)
+	if _, err = c.AddTopic(context.Background(), "MyTopic"); err == nil {
+		t.Errorf("got nil; want error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("got %d calls; want 1", calls.Load())
+	}
+}
+
+func TestRetriesStopOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		writeError(w, http.StatusServiceUnavailable, "")
+	}, WithRetries(5, time.Hour, time.Hour))
+
+	if _, err := c.GetAllTopics(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v; want context.Canceled", err)
+	}
+}
+
+func TestReauth(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/auth":
+			w.Write([]byte("fresh"))
+		case r.Header.Get("Authorization") != "Bearer fresh":
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}, WithCredentials("name", "password"))
+
+	if _, err := c.GetAllTopics(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.Token() != "fresh" {
+		t.Errorf("got token %q; want \"fresh\"", c.Token())
+	}
+}
+
+func TestNotAuthenticated(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+	})
+
+	if _, err := c.GetAllTopics(context.Background()); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("got %v; want ErrNotAuthenticated", err)
+	}
+}
+
+func TestRetriesWithBadBackoff(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 2 {
+			writeError(w, http.StatusServiceUnavailable, "")
+			return
+		}
+		w.Write([]byte(`[]`))
+	}, WithRetries(1, -time.Second, -time.Second))
+
+	if _, err := c.GetAllTopics(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("got %d calls; want 2", calls.Load())
+	}
+}
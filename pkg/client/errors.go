@@ -0,0 +1,75 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrNotAuthenticated is returned when the client has no valid
+	// token and no credentials to obtain one.
+	ErrNotAuthenticated = errors.New("client: not authenticated")
+	// ErrTopicNotFound is returned when the topic does not exist.
+	ErrTopicNotFound = errors.New("client: topic not found")
+	// ErrTitleConflict is returned when a topic with the same
+	// title already exists.
+	ErrTitleConflict = errors.New("client: topic title already exists")
+	// ErrTopicSuspended is returned when a suspended or buried
+	// topic is repeated.
+	ErrTopicSuspended = errors.New("client: topic is suspended or buried")
+	// ErrUserExists is returned when a user with the same
+	// name is already registered.
+	ErrUserExists = errors.New("client: user already exists")
+	// ErrBadRequest is returned when the server rejects the request.
+	ErrBadRequest = errors.New("client: bad request")
+)
+
+// Codes of the errors in the server error bodies.
+var errorCodes = map[string]error{
+	"unauthorized":    ErrNotAuthenticated,
+	"topic_not_found": ErrTopicNotFound,
+	"title_conflict":  ErrTitleConflict,
+	"topic_suspended": ErrTopicSuspended,
+	"user_exists":     ErrUserExists,
+	"bad_request":     ErrBadRequest,
+}
+
+// APIError is an error response of the server. It wraps one of
+// the Err* errors when the server reports a known error code,
+// so it can be checked with errors.Is.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("client: server responded %d %s",
+			e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("client: server responded %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	if err, ok := errorCodes[e.Code]; ok {
+		return err
+	}
+	if e.StatusCode == http.StatusUnauthorized {
+		return ErrNotAuthenticated
+	}
+	return nil
+}
+
+// newAPIError decodes the error body of a response. Bodies which
+// are not JSON, e.g. the ones of proxies, become the message.
+func newAPIError(status int, body []byte) *APIError {
+	e := &APIError{StatusCode: status}
+	if err := json.Unmarshal(body, e); err != nil {
+		e.Code = ""
+		e.Message = string(body)
+	}
+	return e
+}
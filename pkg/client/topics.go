@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+func (c *Client) GetAllTopics(ctx context.Context) ([]Topic, error) {
+	var result []Topic
+	err := c.call(ctx, http.MethodGet, "/topics", nil, &result)
+	return result, err
+}
+
+// GetDueTopics returns up to limit topics due now, the most
+// overdue first. Non-positive limit means all of them.
+func (c *Client) GetDueTopics(ctx context.Context, limit int) ([]Topic, error) {
+	path := "/topics/due"
+	if limit > 0 {
+		path += fmt.Sprintf("?limit=%d", limit)
+	}
+	var result []Topic
+	err := c.call(ctx, http.MethodGet, path, nil, &result)
+	return result, err
+}
+
+// GetTopicsByBox returns the topics grouped by Leitner boxes.
+func (c *Client) GetTopicsByBox(ctx context.Context) ([]TopicBoxGroup, error) {
+	var result []TopicBoxGroup
+	err := c.call(ctx, http.MethodGet, "/topics?group=box", nil, &result)
+	return result, err
+}
+
+func (c *Client) GetTopicById(ctx context.Context, id int) (*Topic, error) {
+	var result Topic
+	if err := c.call(ctx, http.MethodGet, topicPath(id, ""), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AddTopic adds a topic and returns its id.
+func (c *Client) AddTopic(ctx context.Context, title string) (int, error) {
+	var result struct {
+		Id int `json:"id"`
+	}
+	req := struct {
+		Title string `json:"title"`
+	}{title}
+	err := c.call(ctx, http.MethodPost, "/topics", req, &result)
+	return result.Id, err
+}
+
+// RepeatTopic records a repetition of the topic recalled with the grade.
+func (c *Client) RepeatTopic(ctx context.Context, id int, grade Grade) error {
+	req := struct {
+		Grade Grade `json:"grade"`
+	}{grade}
+	return c.call(ctx, http.MethodPatch, topicPath(id, ""), req, nil)
+}
+
+func (c *Client) RemoveTopic(ctx context.Context, id int) error {
+	return c.call(ctx, http.MethodDelete, topicPath(id, ""), nil, nil)
+}
+
+// SuspendTopic excludes the topic from repetitions
+// until it is unsuspended.
+func (c *Client) SuspendTopic(ctx context.Context, id int) error {
+	return c.call(ctx, http.MethodPost, topicPath(id, "/suspend"), nil, nil)
+}
+
+// BuryTopic excludes the topic from repetitions until tomorrow.
+func (c *Client) BuryTopic(ctx context.Context, id int) error {
+	return c.call(ctx, http.MethodPost, topicPath(id, "/bury"), nil, nil)
+}
+
+// UnsuspendTopic returns a suspended or buried topic to repetitions.
+func (c *Client) UnsuspendTopic(ctx context.Context, id int) error {
+	return c.call(ctx, http.MethodPost, topicPath(id, "/unsuspend"), nil, nil)
+}
+
+// GetTopicReviews returns the history of repetitions of the topic.
+func (c *Client) GetTopicReviews(ctx context.Context, id int) ([]ReviewEvent, error) {
+	var result []ReviewEvent
+	err := c.call(ctx, http.MethodGet, topicPath(id, "/reviews"), nil, &result)
+	return result, err
+}
+
+// GetReviews returns the history of repetitions of all topics.
+func (c *Client) GetReviews(ctx context.Context) ([]ReviewEvent, error) {
+	var result []ReviewEvent
+	err := c.call(ctx, http.MethodGet, "/reviews", nil, &result)
+	return result, err
+}
+
+func (c *Client) GetSettings(ctx context.Context) (*Settings, error) {
+	var result Settings
+	if err := c.call(ctx, http.MethodGet, "/settings", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) UpdateSettings(ctx context.Context, settings *Settings) error {
+	return c.call(ctx, http.MethodPut, "/settings", settings, nil)
+}
+
+// GetVacation returns the planned or current vacation
+// or nil if there is none.
+func (c *Client) GetVacation(ctx context.Context) (*Vacation, error) {
+	var result *Vacation
+	err := c.call(ctx, http.MethodGet, "/vacation", nil, &result)
+	return result, err
+}
+
+func (c *Client) SetVacation(ctx context.Context, vacation *Vacation) error {
+	return c.call(ctx, http.MethodPut, "/vacation", vacation, nil)
+}
+
+// EndVacation cancels the planned vacation or ends the current one.
+func (c *Client) EndVacation(ctx context.Context) error {
+	return c.call(ctx, http.MethodDelete, "/vacation", nil, nil)
+}
+
+// GetForecast returns the number of topics due
+// on each of the following days.
+func (c *Client) GetForecast(ctx context.Context, days int) ([]ForecastDay, error) {
+	var result struct {
+		Days []ForecastDay `json:"days"`
+	}
+	path := fmt.Sprintf("/stats/forecast?days=%d", days)
+	err := c.call(ctx, http.MethodGet, path, nil, &result)
+	return result.Days, err
+}
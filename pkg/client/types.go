@@ -0,0 +1,210 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Topic is a topic the user repeats.
+type Topic struct {
+	Id           int         `json:"id"`
+	Title        string      `json:"title"`
+	Created      time.Time   `json:"created"`
+	LastRepeated time.Time   `json:"lastRepeated"`
+	NextRepeat   time.Time   `json:"nextRepeat"`
+	State        ReviewState `json:"state"`
+	// Suspended topics are not repeated until they are unsuspended.
+	Suspended bool `json:"suspended"`
+	// BuriedUntil is the time until which the topic is not repeated.
+	BuriedUntil time.Time `json:"buriedUntil"`
+}
+
+// Active reports whether the topic is neither suspended nor buried.
+func (t *Topic) Active(now time.Time) bool {
+	return !t.Suspended && !now.Before(t.BuriedUntil)
+}
+
+// TopicBoxGroup is a group of topics from the same Leitner box.
+type TopicBoxGroup struct {
+	Box    int      `json:"box"`
+	Topics []*Topic `json:"topics"`
+}
+
+// ReviewState holds the scheduling progress of a topic.
+// Which fields are used depends on the scheduler.
+type ReviewState struct {
+	Version     int           `json:"version"`
+	Step        int           `json:"step"`
+	Relearning  bool          `json:"relearning"`
+	Lapses      int           `json:"lapses"`
+	Level       int           `json:"level"`
+	Interval    time.Duration `json:"interval"`
+	EaseFactor  float64       `json:"easeFactor"`
+	Repetitions int           `json:"repetitions"`
+	Stability   float64       `json:"stability"`
+	Difficulty  float64       `json:"difficulty"`
+	Box         int           `json:"box"`
+}
+
+// Stages of learning a topic.
+const (
+	StageNew        = "new"
+	StageLearning   = "learning"
+	StageRelearning = "relearning"
+	StageReview     = "review"
+)
+
+// Stage returns the stage of learning the topic is in.
+func (s ReviewState) Stage() string {
+	switch {
+	case s.Interval == 0:
+		return StageNew
+	case s.Relearning:
+		return StageRelearning
+	case s.Interval < 24*time.Hour:
+		return StageLearning
+	default:
+		return StageReview
+	}
+}
+
+// Grade is the quality of a recall on the SuperMemo scale from 0 to 5.
+// Grades below GradeHard mean the topic was not remembered.
+type Grade int
+
+const (
+	GradeForgot   Grade = 0
+	GradeAgain    Grade = 1
+	GradeFamiliar Grade = 2
+	GradeHard     Grade = 3
+	GradeGood     Grade = 4
+	GradeEasy     Grade = 5
+)
+
+// gradeNames are the names of the grades in the order of their values.
+var gradeNames = [...]string{"forgot", "again", "familiar", "hard", "good", "easy"}
+
+// Passed reports whether the topic was remembered.
+func (g Grade) Passed() bool {
+	return g >= GradeHard
+}
+
+// String returns the name of the grade or its number
+// if it is out of the scale.
+func (g Grade) String() string {
+	if g < GradeForgot || g > GradeEasy {
+		return strconv.Itoa(int(g))
+	}
+	return gradeNames[g]
+}
+
+// ParseGrade parses a grade given either as a number from 0 to 5
+// or as its name, e.g. "good".
+func ParseGrade(s string) (Grade, error) {
+	for g, name := range gradeNames {
+		if s == name {
+			return Grade(g), nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < int(GradeForgot) || n > int(GradeEasy) {
+		return 0, fmt.Errorf("invalid grade %q", s)
+	}
+	return Grade(n), nil
+}
+
+// ReviewEvent is a record of a single topic repetition.
+type ReviewEvent struct {
+	TopicId      int           `json:"topicId"`
+	Time         time.Time     `json:"time"`
+	Grade        Grade         `json:"grade"`
+	PrevInterval time.Duration `json:"prevInterval"`
+	NewInterval  time.Duration `json:"newInterval"`
+	Elapsed      time.Duration `json:"elapsed"`
+}
+
+// Settings holds preferences of the user.
+// Zero values mean the server defaults.
+type Settings struct {
+	Scheduler           string     `json:"scheduler"`
+	RetentionTarget     float64    `json:"retentionTarget"`
+	FSRSWeights         []float64  `json:"fsrsWeights"`
+	LeitnerIntervals    []Duration `json:"leitnerIntervals"`
+	LearningSteps       []Duration `json:"learningSteps"`
+	RelearningSteps     []Duration `json:"relearningSteps"`
+	GraduatingIntervals []Duration `json:"graduatingIntervals"`
+	MaxInterval         Duration   `json:"maxInterval"`
+	DisableFuzz         bool       `json:"disableFuzz"`
+	LoadBalance         bool       `json:"loadBalance"`
+	Vacation            *Vacation  `json:"vacation,omitempty"`
+}
+
+// Ways to deal with topics which fall due during a vacation.
+const (
+	VacationShift  = "shift"
+	VacationSpread = "spread"
+)
+
+// Vacation is a period when the user does not repeat topics.
+type Vacation struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Mode       string    `json:"mode"`
+	SpreadDays int       `json:"spreadDays"`
+}
+
+// ForecastDay is the number of topics due on the date
+// formatted as YYYY-MM-DD.
+type ForecastDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// Duration is a time.Duration which is sent as a string
+// like "20m" or "30d".
+type Duration time.Duration
+
+func (d Duration) String() string {
+	td := time.Duration(d)
+	if td != 0 && td%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", td/(24*time.Hour))
+	}
+	return td.String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// ParseDuration parses a duration given as a number of days
+// like "30d" or in the format of time.ParseDuration.
+func ParseDuration(s string) (Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return Duration(time.Duration(n) * 24 * time.Hour), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return Duration(d), nil
+}
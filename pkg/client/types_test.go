@@ -0,0 +1,39 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGrade(t *testing.T) {
+	for g := GradeForgot; g <= GradeEasy; g++ {
+		parsed, err := ParseGrade(g.String())
+		if err != nil || parsed != g {
+			t.Errorf("got %d, %v parsing %q; want %d", parsed, err, g.String(), g)
+		}
+	}
+	if g, err := ParseGrade("4"); err != nil || g != GradeGood {
+		t.Errorf("got %d, %v parsing \"4\"; want %d", g, err, GradeGood)
+	}
+	for _, s := range []string{"6", "-1", "meh"} {
+		if _, err := ParseGrade(s); err == nil {
+			t.Errorf("on %q got nil; want error", s)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want time.Duration
+	}{
+		{"20m", 20 * time.Minute},
+		{"30d", 30 * 24 * time.Hour},
+	}
+	for _, test := range tests {
+		d, err := ParseDuration(test.in)
+		if err != nil || time.Duration(d) != test.want {
+			t.Errorf("got %s, %v parsing %q; want %s", time.Duration(d), err, test.in, test.want)
+		}
+	}
+}